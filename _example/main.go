@@ -22,7 +22,7 @@ func parse() {
 		true_var = true
 		false_var = false
 		int_var = 9223372036854775807
-		float_var = 3.14.
+		float_var = 3.14
 		array_var = ["some", "data"]
 		# comment
 		map_var = {
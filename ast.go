@@ -16,9 +16,88 @@ type Node interface {
 	Tkn() Token
 }
 
+// Comments contains the comments attached to a node in the document
+type Comments struct {
+	// Leading comments are found on the lines directly before the node
+	Leading []Token
+	// Inline is a comment found on the same line after the node
+	Inline *Token
+	// Trailing comments are found after the final node in a document, block, map or slice
+	Trailing []Token
+}
+
+// wrap places the comments around the string representation of the node they are attached to
+func (c *Comments) wrap(s string) string {
+	if c == nil {
+		return s
+	}
+
+	var buf bytes.Buffer
+
+	for _, comment := range c.Leading {
+		buf.WriteString(comment.Literal + "\n")
+	}
+
+	buf.WriteString(s)
+
+	if c.Inline != nil {
+		buf.WriteString(" " + c.Inline.Literal)
+	}
+
+	for _, comment := range c.Trailing {
+		buf.WriteString("\n" + comment.Literal)
+	}
+
+	return buf.String()
+}
+
+// all returns every comment in the order they appear
+func (c *Comments) all() []Token {
+	var comments []Token
+
+	comments = append(comments, c.Leading...)
+	if c.Inline != nil {
+		comments = append(comments, *c.Inline)
+	}
+
+	return append(comments, c.Trailing...)
+}
+
+// commentText strips the comment markers from a comment token leaving only the text
+func commentText(tkn Token) string {
+	switch {
+	case strings.HasPrefix(tkn.Literal, "/*"):
+		return strings.TrimSpace(strings.TrimSuffix(tkn.Literal[2:], "*/"))
+	case strings.HasPrefix(tkn.Literal, "//"):
+		return strings.TrimPrefix(tkn.Literal[2:], " ")
+	}
+
+	return strings.TrimPrefix(strings.TrimPrefix(tkn.Literal, "#"), " ")
+}
+
+// commentToken builds a comment token from a string of text
+func commentToken(text string) Token {
+	if text == "" {
+		return Token{Type: TknComment, Literal: "#"}
+	}
+
+	return Token{Type: TknComment, Literal: "# " + text}
+}
+
+// commented is implemented by nodes that are able to have comments attached to them
+type commented interface {
+	Node
+	comments() *Comments
+}
+
 // Ast contains the Abstract Syntax Tree of an icl ducument
 type Ast struct {
 	Nodes []Node
+	// Comments contains any comments that could not be attached to a node
+	Comments []Token
+
+	// source is the document the Ast was parsed from, it is used to render excerpts in errors
+	source string
 }
 
 // Version returns the version of the ICL document contained in the Ast
@@ -37,7 +116,7 @@ func (n *Ast) Version() int {
 		return 0
 	}
 
-	i, err := strconv.ParseInt(value.Value, 10, 64)
+	i, err := parseInt(value.Value, 64)
 	if err != nil {
 		return 0
 	}
@@ -45,15 +124,27 @@ func (n *Ast) Version() int {
 	return int(i)
 }
 
+// Value converts the document contained in the Ast into a generic Value tree
+func (a Ast) Value() (*Value, error) {
+	value, err := nodesValue(a.Nodes, "")
+	if err != nil {
+		if de, ok := err.(*DecodeError); ok {
+			de.Excerpt = excerpt(a.source, de.Line, de.Pos)
+		}
+		return nil, err
+	}
+
+	return value, nil
+}
+
 // Unmarshal fillso out the provided struct pointer with the data in the AST
 func (a Ast) Unmarshal(v any) error {
-	rv := reflect.ValueOf(v)
-	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
-		return &InvalidUnmarshalError{reflect.TypeOf(v)}
-	}
+	return a.UnmarshalWithOptions(v, DecodeOptions{})
+}
 
-	d := NewDecoder(a, rv.Elem())
-	return d.decode()
+// UnmarshalWithOptions fills out the provided struct pointer with the data in the AST using the provided options
+func (a Ast) UnmarshalWithOptions(v any, opts DecodeOptions) error {
+	return NewDecoder(a, reflect.Value{}, opts).Decode(v)
 }
 
 // String implements Node
@@ -64,18 +155,16 @@ func (n *Ast) String() string {
 		buf.WriteString(stmt.String() + "\n")
 	}
 
+	for _, comment := range n.Comments {
+		buf.WriteString(comment.Literal + "\n")
+	}
+
 	return buf.String()
 }
 
 // Bytes returns the byte array representaiton of the output icl string
 func (n *Ast) Bytes() []byte {
-	var buf bytes.Buffer
-
-	for _, stmt := range n.Nodes {
-		buf.WriteString(stmt.String() + "\n")
-	}
-
-	return buf.Bytes()
+	return []byte(n.String())
 }
 
 // TokenLiteral implements Node
@@ -203,14 +292,42 @@ var _ Node = (*NullNode)(nil)
 type SliceNode struct {
 	Token    Token
 	Elements []Node
+	// ElementComments contains the comments attached to the element at the same index
+	ElementComments []*Comments
+	// Comments contains any comments found in an otherwise empty slice
+	Comments []Token
 }
 
 // String implements Node
 func (n *SliceNode) String() string {
-	if len(n.Elements) == 0 {
+	if len(n.Elements) == 0 && len(n.Comments) == 0 {
 		return "[]"
 	}
+
 	var buf bytes.Buffer
+
+	// comments can only be preserved if each element is written on its own line
+	if len(n.ElementComments) > 0 || len(n.Comments) > 0 {
+		buf.WriteString("[\n")
+
+		for i, elem := range n.Elements {
+			var comments *Comments
+			if i < len(n.ElementComments) {
+				comments = n.ElementComments[i]
+			}
+
+			buf.WriteString(indent(comments.wrap(elem.String()+",")) + "\n")
+		}
+
+		for _, comment := range n.Comments {
+			buf.WriteString(indent(comment.Literal) + "\n")
+		}
+
+		buf.WriteString("]")
+
+		return buf.String()
+	}
+
 	buf.WriteString("[")
 
 	for i, elem := range n.Elements {
@@ -277,33 +394,56 @@ var _ Node = (*CollectionNode)(nil)
 type MapNode struct {
 	Token    Token
 	Elements map[Node]Node
+	// EntryComments contains the comments attached to each entry, keyed by the entries key node
+	EntryComments map[Node]*Comments
+	// Comments contains any comments found in an otherwise empty map
+	Comments []Token
 }
 
 // String implements Node
 func (n *MapNode) String() string {
 	var buf bytes.Buffer
 
-	if len(n.Elements) == 0 {
+	if len(n.Elements) == 0 && len(n.Comments) == 0 {
 		return "{}"
 	}
 
 	buf.WriteString("{\n")
 
+	for _, key := range n.Keys() {
+		entry := fmt.Sprintf("%s: %s,", key.String(), n.Elements[key].String())
+		buf.WriteString(indent(n.EntryComments[key].wrap(entry)) + "\n")
+	}
+
+	for _, comment := range n.Comments {
+		buf.WriteString(indent(comment.Literal) + "\n")
+	}
+
+	buf.WriteString("}")
+
+	return buf.String()
+}
+
+// Keys returns the keys of the map in the order they appear in the document
+func (n *MapNode) Keys() []Node {
 	keys := make([]Node, 0, len(n.Elements))
 	for key := range n.Elements {
 		keys = append(keys, key)
 	}
+
 	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i].Tkn(), keys[j].Tkn()
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Pos != b.Pos {
+			return a.Pos < b.Pos
+		}
+
 		return keys[i].String() < keys[j].String()
 	})
 
-	for _, key := range keys {
-		buf.WriteString(indent(fmt.Sprintf("%s: %s,", key.String(), n.Elements[key].String())) + "\n")
-	}
-
-	buf.WriteString("}")
-
-	return buf.String()
+	return keys
 }
 
 // TokenLiteral implements Node
@@ -317,9 +457,10 @@ func (n *MapNode) Tkn() Token {
 var _ Node = (*MapNode)(nil)
 
 type AssignNode struct {
-	Token Token
-	Name  *Identifier
-	Value Node
+	Token    Token
+	Name     *Identifier
+	Value    Node
+	Comments Comments
 }
 
 // String implements Node
@@ -333,7 +474,11 @@ func (n *AssignNode) String() string {
 		buf.WriteString(n.Value.String())
 	}
 
-	return buf.String()
+	return n.Comments.wrap(buf.String())
+}
+
+func (n *AssignNode) comments() *Comments {
+	return &n.Comments
 }
 
 // statementNode implements Node
@@ -349,12 +494,16 @@ func (n *AssignNode) Tkn() Token {
 	return n.Token
 }
 
-var _ Node = (*AssignNode)(nil)
+var _ commented = (*AssignNode)(nil)
 
 type BlockNode struct {
 	Token      Token
 	Parameters []Token
 	Body       *BlockBodyNode
+	Comments   Comments
+
+	// dotted is set on blocks that were expanded from a dotted key
+	dotted bool
 }
 
 // String implements Node
@@ -369,7 +518,11 @@ func (n *BlockNode) String() string {
 
 	buf.WriteString(n.Body.String())
 
-	return buf.String()
+	return n.Comments.wrap(buf.String())
+}
+
+func (n *BlockNode) comments() *Comments {
+	return &n.Comments
 }
 
 // TokenLiteral implements Node
@@ -380,11 +533,13 @@ func (n *BlockNode) Tkn() Token {
 	return n.Token
 }
 
-var _ Node = (*BlockNode)(nil)
+var _ commented = (*BlockNode)(nil)
 
 type BlockBodyNode struct {
 	Token Token
 	Nodes []Node
+	// Comments contains any comments that could not be attached to a node
+	Comments []Token
 }
 
 // String implements Node
@@ -397,6 +552,10 @@ func (n *BlockBodyNode) String() string {
 		buf.WriteString(indent(stmt.String()) + "\n")
 	}
 
+	for _, comment := range n.Comments {
+		buf.WriteString(indent(comment.Literal) + "\n")
+	}
+
 	buf.WriteString("}")
 
 	return buf.String()
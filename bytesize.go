@@ -0,0 +1,171 @@
+package icl
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a number of bytes that can be written in a document with a unit suffix eg "512MiB", "2GB" or "64k"
+//
+// IEC units (KiB, MiB, ...) and single letter units (k, m, ...) are powers of 1024, SI units (KB, MB, ...) are
+// powers of 1000. a plain number is taken as a number of bytes
+type ByteSize int64
+
+const (
+	Byte ByteSize = 1
+
+	KiB = 1024 * Byte
+	MiB = 1024 * KiB
+	GiB = 1024 * MiB
+	TiB = 1024 * GiB
+	PiB = 1024 * TiB
+	EiB = 1024 * PiB
+
+	KB = 1000 * Byte
+	MB = 1000 * KB
+	GB = 1000 * MB
+	TB = 1000 * GB
+	PB = 1000 * TB
+	EB = 1000 * PB
+)
+
+var byteSizeType = reflect.TypeFor[ByteSize]()
+
+// byteUnits maps the lower case unit suffixes to their size
+var byteUnits = map[string]ByteSize{
+	"": Byte, "b": Byte,
+	"k": KiB, "ki": KiB, "kib": KiB, "kb": KB,
+	"m": MiB, "mi": MiB, "mib": MiB, "mb": MB,
+	"g": GiB, "gi": GiB, "gib": GiB, "gb": GB,
+	"t": TiB, "ti": TiB, "tib": TiB, "tb": TB,
+	"p": PiB, "pi": PiB, "pib": PiB, "pb": PB,
+	"e": EiB, "ei": EiB, "eib": EiB, "eb": EB,
+}
+
+// ParseByteSize parses a size with an optional unit suffix eg "512MiB", "1.5GB" or "64k"
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return !strings.ContainsRune("0123456789._+-", r)
+	})
+	if i < 0 {
+		i = len(s)
+	}
+
+	num, unit := s[:i], strings.TrimSpace(s[i:])
+
+	size, ok := byteUnits[strings.ToLower(unit)]
+	if !ok || num == "" {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+
+	if !strings.Contains(num, ".") {
+		n, err := strconv.ParseInt(strings.ReplaceAll(num, "_", ""), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid byte size %q", s)
+		}
+
+		if n != 0 && (n*int64(size))/int64(size) != n {
+			return 0, fmt.Errorf("byte size %q is out of range", s)
+		}
+
+		return ByteSize(n) * size, nil
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+
+	f *= float64(size)
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("byte size %q is not a whole number of bytes", s)
+	}
+
+	if f >= math.MaxInt64 || f < math.MinInt64 {
+		return 0, fmt.Errorf("byte size %q is out of range", s)
+	}
+
+	return ByteSize(f), nil
+}
+
+// String formats the size using the largest unit that represents it exactly, see formatByteSize
+func (b ByteSize) String() string {
+	if b < 0 {
+		return formatByteSize(true, uint64(-b))
+	}
+
+	return formatByteSize(false, uint64(b))
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (b ByteSize) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	size, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+
+	*b = size
+	return nil
+}
+
+// formatByteSize formats the size using the largest IEC or SI unit that divides it exactly, IEC units are preferred
+// when both are the same size so 536870912 becomes 512MiB and 2000000000 becomes 2GB
+func formatByteSize(negative bool, n uint64) string {
+	var sign string
+	if negative {
+		sign = "-"
+	}
+
+	if n == 0 {
+		return "0B"
+	}
+
+	iec, iecUnit := largestUnit(n, 1024)
+	si, siUnit := largestUnit(n, 1000)
+
+	switch {
+	case iecUnit == 0 && siUnit == 0:
+		return sign + strconv.FormatUint(n, 10) + "B"
+	case iecUnit >= siUnit:
+		return sign + strconv.FormatUint(iec, 10) + " KMGTPE"[iecUnit:iecUnit+1] + "iB"
+	default:
+		return sign + strconv.FormatUint(si, 10) + " KMGTPE"[siUnit:siUnit+1] + "B"
+	}
+}
+
+// largestUnit divides n by the base for as long as it divides exactly
+// the result is returned along with the number of divisions
+func largestUnit(n, base uint64) (uint64, int) {
+	var unit int
+	for unit < 6 && n%base == 0 {
+		n /= base
+		unit++
+	}
+
+	return n, unit
+}
+
+// isIntegerType checks if the type, or the type it points to, is an int or uint kind
+func isIntegerType(rt reflect.Type) bool {
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+
+	switch rt.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+
+	return false
+}
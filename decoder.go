@@ -3,12 +3,22 @@ package icl
 import (
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"reflect"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 )
 
-var errFieldNotFound = errors.New("field not found")
+var (
+	errFieldNotFound = errors.New("field not found")
+	errUnknownField  = errors.New("unknown field")
+	errMissingField  = errors.New("required field is missing")
+)
 
 type InvalidUnmarshalError struct {
 	Type reflect.Type
@@ -25,22 +35,190 @@ func (e *InvalidUnmarshalError) Error() string {
 	return "icl: Unmarshal(nil " + e.Type.String() + ")"
 }
 
+// MissingField describes a required field that has no assignment or block in the document
+type MissingField struct {
+	// Path is the full path to the field eg .server.tls.cert
+	Path string
+	// Block is the path of the enclosing block, this will be empty for fields in the root of the document
+	Block string
+	// Line is the line of the nearest enclosing block found in the document, this will be 0 if there is none
+	Line int
+}
+
+// MissingFieldsError is returned when one or more required fields are not set by the document
+type MissingFieldsError struct {
+	Fields []MissingField
+}
+
+// Error implements error
+func (e *MissingFieldsError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		switch {
+		case f.Block == "":
+			msgs = append(msgs, f.Path)
+			continue
+		case f.Line == 0:
+			msgs = append(msgs, fmt.Sprintf("%s -- [block(%s)]", f.Path, f.Block))
+			continue
+		}
+
+		msgs = append(msgs, fmt.Sprintf("%s -- [block(%s) line(%d)]", f.Path, f.Block, f.Line))
+	}
+
+	return "icl: missing required fields\n" + strings.Join(msgs, "\n")
+}
+
+// UnknownField describes an assignment or block in the document that does not match any struct field
+type UnknownField struct {
+	// Path is the full path to the assignment or block eg .server.tls.cert
+	Path string
+	Line int
+	Pos  int
+}
+
+// UnknownFieldsError is returned when DisallowUnknownFields is set and the document contains one or more
+// assignments or blocks that do not match a struct field
+type UnknownFieldsError struct {
+	Fields []UnknownField
+}
+
+// Error implements error
+func (e *UnknownFieldsError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, fmt.Sprintf("%s -- [line(%d) pos(%d)]", f.Path, f.Line, f.Pos))
+	}
+
+	return "icl: unknown fields\n" + strings.Join(msgs, "\n")
+}
+
+// DecodeError describes a value in the document that could not be decoded
+type DecodeError struct {
+	// Path is the full path to the field eg .server.tls.cert
+	Path string
+	Line int
+	Pos  int
+	// Expected is the kind of the target field, this will be reflect.Invalid if it is not known
+	Expected reflect.Kind
+	// Found is the token type of the node in the document, this will be empty if there was no node
+	Found TokenType
+	Err   error
+	// Excerpt is the offending line of the document with a caret pointing at the value
+	Excerpt string
+}
+
+// Error implements error
+func (e *DecodeError) Error() string {
+	msg := fmt.Sprintf("%s: %v", e.Path, e.Err)
+	if e.Line != 0 {
+		msg += fmt.Sprintf("\nline(%d) pos(%d)", e.Line, e.Pos)
+	}
+
+	if e.Excerpt != "" {
+		msg += "\n" + e.Excerpt
+	}
+
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeErrors is returned when CollectErrors is set and one or more values could not be decoded
+type DecodeErrors []*DecodeError
+
+// Error implements error
+func (e DecodeErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+
+	return "icl: decode errors\n" + strings.Join(msgs, "\n")
+}
+
+// Unwrap returns each of the collected errors
+func (e DecodeErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// DecodeOptions configures the behaviour of the Decoder
+type DecodeOptions struct {
+	// DisallowUnknownFields causes the Decoder to return an error for every assignment or block in the document
+	// that does not match a struct field
+	DisallowUnknownFields bool
+	// CollectErrors causes the Decoder to keep going after a value fails to decode, every failure (including
+	// unknown and missing fields) will be returned in a single DecodeErrors value
+	CollectErrors bool
+}
+
 type Decoder struct {
 	ast          Ast
 	target       reflect.Value
 	paramCounter int
 	blockMap     map[reflect.Value]map[string]struct{}
-	recover      error
+	recover      *DecodeError
 	line         int
 	pos          int
+	missing      []MissingField
+	unknown      []UnknownField
+	errs         DecodeErrors
+	opts         DecodeOptions
+	// layout is the time layout from the tag of the field currently being assigned
+	layout string
+	// bytes is set when the field currently being assigned has the bytes tag option
+	bytes bool
+	// parser is the source of the nodes when decoding from a reader
+	parser *Parser
 }
 
-func NewDecoder(a Ast, target reflect.Value) *Decoder {
-	return &Decoder{
+func NewDecoder(a Ast, target reflect.Value, opts ...DecodeOptions) *Decoder {
+	d := &Decoder{
 		ast:      a,
 		target:   target,
 		blockMap: make(map[reflect.Value]map[string]struct{}),
 	}
+
+	if len(opts) > 0 {
+		d.opts = opts[0]
+	}
+
+	return d
+}
+
+// NewDecoderFromReader creates a Decoder that parses the document from r while it is being decoded
+//
+// top level nodes are decoded as soon as they have been parsed so the document is never held in memory in full,
+// as a result the first parse error will stop the decode with any earlier nodes already assigned to the target
+func NewDecoderFromReader(r io.Reader, opts ...DecodeOptions) *Decoder {
+	d := NewDecoder(Ast{}, reflect.Value{}, opts...)
+	d.parser = NewParser(NewLexerFromReader(r))
+
+	return d
+}
+
+// Decode decodes the document into the struct pointer v
+func (d *Decoder) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return &InvalidUnmarshalError{reflect.TypeOf(v)}
+	}
+
+	d.target = rv.Elem()
+
+	if d.parser != nil {
+		return d.decodeStream()
+	}
+
+	return d.decode()
 }
 
 func (d *Decoder) decode() error {
@@ -50,37 +228,223 @@ func (d *Decoder) decode() error {
 		}
 	}
 
-	return nil
+	return d.finish(d.ast.Nodes, d.ast.Comments)
+}
+
+// decodeStream decodes the top level nodes from the parser as they are parsed
+//
+// only an outline of each node is kept hold of once it has been decoded, this is enough to apply defaults and
+// fill .comments fields without keeping the values in memory
+func (d *Decoder) decodeStream() error {
+	var (
+		nodes []Node
+		last  Node
+		// blocks without params can still have dotted keys merged into them until a later block with the same name
+		// replaces them as the merge target, only the current target for each name is held
+		held = make(map[string]*BlockNode)
+	)
+
+	for node, err := range d.parser.Nodes() {
+		if err != nil {
+			if diag, ok := err.(*Diagnostic); ok {
+				return &ParseError{Diagnostics: []*Diagnostic{diag}}
+			}
+			return err
+		}
+
+		if block, ok := node.(*BlockNode); ok && len(block.Parameters) == 0 {
+			prev := held[block.Token.Literal]
+			held[block.Token.Literal] = block
+
+			if prev != nil {
+				if err := d.node(prev, d.target, ""); err != nil {
+					return err
+				}
+			}
+		} else if err := d.node(node, d.target, ""); err != nil {
+			return err
+		}
+
+		if last != nil {
+			nodes = append(nodes, outline(last))
+		}
+
+		// the final node is kept in full as its trailing comments are only attached once the document has ended
+		last = node
+	}
+
+	if last != nil {
+		nodes = append(nodes, last)
+	}
+
+	remaining := slices.SortedFunc(maps.Values(held), func(a, b *BlockNode) int {
+		if a.Token.Line != b.Token.Line {
+			return a.Token.Line - b.Token.Line
+		}
+
+		return a.Token.Pos - b.Token.Pos
+	})
+
+	for _, block := range remaining {
+		if err := d.node(block, d.target, ""); err != nil {
+			return err
+		}
+	}
+
+	return d.finish(nodes, d.parser.comments)
+}
+
+// outline returns a copy of the node without its value or body
+func outline(node Node) Node {
+	switch n := node.(type) {
+	case *AssignNode:
+		return &AssignNode{Token: n.Token, Name: n.Name, Comments: n.Comments}
+	case *BlockNode:
+		return &BlockNode{Token: n.Token, Comments: n.Comments}
+	}
+
+	return node
+}
+
+// finish applies defaults and comments to the target once every node has been decoded
+func (d *Decoder) finish(nodes []Node, dangling []Token) error {
+	if err := d.unsetFields(nodes, d.target, "", 0); err != nil {
+		return err
+	}
+
+	if err := d.comments(nodes, dangling, d.target, ""); err != nil {
+		return err
+	}
+
+	if d.opts.CollectErrors {
+		return d.collectedErrors()
+	}
+
+	var errs []error
+	if len(d.unknown) > 0 {
+		errs = append(errs, &UnknownFieldsError{Fields: d.unknown})
+	}
+
+	if len(d.missing) > 0 {
+		errs = append(errs, &MissingFieldsError{Fields: d.missing})
+	}
+
+	if len(errs) == 1 {
+		return errs[0]
+	}
+
+	return errors.Join(errs...)
 }
 
 func (d *Decoder) assign(node *AssignNode, target reflect.Value, path string) error {
 	d.line = node.Token.Line
-	d.line = node.Token.Pos
+	d.pos = node.Token.Pos
 
 	v, _, tag, err := d.findTargetField(node.Name, target)
 	if err != nil {
 		if errors.Is(errFieldNotFound, err) {
+			d.unknownField(path+"."+node.Name.Value, node.Token)
 			return nil
 		}
 		return err
 	}
 
-	rv := *v
 	path += "." + tag.key
-
-	rk := rv.Kind()
-	if rk == reflect.Ptr {
-		rk = rv.Type().Elem().Kind()
-	}
+	d.layout = tag.layout
+	d.bytes = tag.bytes
 
 	defer func() {
+		d.layout = ""
+		d.bytes = false
+
 		if err := recover(); err != nil {
-			d.recover = fmt.Errorf("%s: %v", path, err)
+			d.recover = &DecodeError{Path: path, Line: d.line, Pos: d.pos, Err: fmt.Errorf("%v", err)}
 		}
 	}()
 
+	return d.assignValue(node.Value, *v, path)
+}
+
+// assignValue decodes the value node into the reflect value
+func (d *Decoder) assignValue(value Node, rv reflect.Value, path string) error {
+	d.line = value.Tkn().Line
+	d.pos = value.Tkn().Pos
+
+	if _, ok := value.(*NullNode); ok && rv.Kind() == reflect.Ptr {
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	}
+
+	if u, ok := unmarshaler(rv); ok {
+		if err := u.UnmarshalICL(value); err != nil {
+			return d.fail(path, rv, value, &DecodeError{
+				Line:     d.line,
+				Pos:      d.pos,
+				Expected: baseKind(rv),
+				Found:    value.Tkn().Type,
+				Err:      err,
+			})
+		}
+
+		return nil
+	}
+
+	if str, ok := stringValue(value); ok && (isType(rv.Type(), durationType) || isType(rv.Type(), timeType)) {
+		if err := d.assignTime(str, rv); err != nil {
+			return d.fail(path, rv, value, &DecodeError{
+				Line:     d.line,
+				Pos:      d.pos,
+				Expected: baseKind(rv),
+				Found:    value.Tkn().Type,
+				Err:      err,
+			})
+		}
+
+		return nil
+	}
+
+	if (d.bytes && isIntegerType(rv.Type())) || isType(rv.Type(), byteSizeType) {
+		if size, ok, err := byteSizeValue(value); ok {
+			if err == nil {
+				err = assignByteSize(size, rv)
+			}
+
+			if err != nil {
+				return d.fail(path, rv, value, &DecodeError{
+					Line:     d.line,
+					Pos:      d.pos,
+					Expected: baseKind(rv),
+					Found:    value.Tkn().Type,
+					Err:      err,
+				})
+			}
+
+			return nil
+		}
+	}
+
+	if str, ok := value.(*StringNode); ok {
+		if u, ok := textUnmarshaler(rv); ok {
+			if err := u.UnmarshalText([]byte(str.Value)); err != nil {
+				return d.fail(path, rv, value, &DecodeError{
+					Line:     d.line,
+					Pos:      d.pos,
+					Expected: baseKind(rv),
+					Found:    str.Token.Type,
+					Err:      err,
+				})
+			}
+
+			return nil
+		}
+	}
+
+	rk := rv.Kind()
+	if rk == reflect.Ptr {
+		rk = rv.Type().Elem().Kind()
+	}
+
 	var setErr error
-switcher:
 	switch rk {
 	// primatives
 	case reflect.String,
@@ -89,102 +453,136 @@ switcher:
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
 		reflect.Float32, reflect.Float64:
 
-		d.line = node.Value.Tkn().Line
-		d.line = node.Value.Tkn().Pos
-
-		setErr = d.assignPrimitiveNode(node.Value, rv, false)
+		setErr = d.assignPrimitiveNode(value, rv)
 
 	// complex types
 	case reflect.Slice:
-		val, ok := node.Value.(*SliceNode)
+		val, ok := value.(*SliceNode)
 		if !ok {
-			return errors.New("node is not a slice")
+			setErr = errors.New("node is not a slice")
+			break
+		}
+
+		if rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
 		}
 
 		for _, entry := range val.Elements {
-			d.line = entry.Tkn().Line
-			d.line = entry.Tkn().Pos
+			elem, ok, err := d.assignElement(entry, rv.Type().Elem(), path)
+			if err != nil {
+				return err
+			}
 
-			if rv.Kind() == reflect.Ptr {
-				if err := d.assignPrimitiveNode(entry, rv.Elem(), true); err != nil {
-					setErr = err
-					break switcher
-				}
-			} else {
-				if err := d.assignPrimitiveNode(entry, rv, true); err != nil {
-					setErr = err
-					break switcher
-				}
+			if ok {
+				rv.Set(reflect.Append(rv, elem))
 			}
 		}
 
 	case reflect.Map:
-		val, ok := node.Value.(*MapNode)
+		val, ok := value.(*MapNode)
 		if !ok {
 			setErr = errors.New("node is not a map")
-			break switcher
+			break
 		}
 
 		if !rv.IsValid() {
 			setErr = errors.New("invalid map")
-			break switcher
+			break
 		}
 
-		if rv.IsNil() {
-			rv.Set(reflect.MakeMap(rv.Type()))
+		if rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
 		}
 
-		for key, value := range val.Elements {
-			d.line = value.Tkn().Line
-			d.line = value.Tkn().Pos
-
-			t := reflect.New(rv.Type().Elem())
+		if rv.Type().Key().Kind() != reflect.String {
+			setErr = errors.New("Map keys must be a string")
+			break
+		}
 
-			var isSlice bool
-			rt := rv.Type()
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMap(rv.Type()))
+		}
 
-			if rt.Kind() == reflect.Slice {
-				rt = rt.Elem()
-				isSlice = true
+		for _, key := range val.Keys() {
+			elem, ok, err := d.assignElement(val.Elements[key], rv.Type().Elem(), path)
+			if err != nil {
+				return err
 			}
 
-			if err := d.assignPrimitiveNode(value, t.Elem(), isSlice); err != nil {
-				setErr = err
-				break switcher
+			if !ok {
+				continue
 			}
 
 			// NB: this looks like pointless repetition but go uses the common interface type if i
 			//     try to do this with a single case of *StringNode, *Identifier
 			switch k := key.(type) {
 			case *StringNode:
-				rv.SetMapIndex(reflect.ValueOf(k.Value), t.Elem())
+				rv.SetMapIndex(reflect.ValueOf(k.Value).Convert(rv.Type().Key()), elem)
 			case *Identifier:
-				rv.SetMapIndex(reflect.ValueOf(k.Value), t.Elem())
+				rv.SetMapIndex(reflect.ValueOf(k.Value).Convert(rv.Type().Key()), elem)
 			default:
-				setErr = errors.New("Map keys must be a string")
-				break switcher
+				if err := d.fail(path, rv, key, errors.New("Map keys must be a string")); err != nil {
+					return err
+				}
+			}
+		}
+
+	case reflect.Interface:
+		if rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				rv.Set(reflect.New(rv.Type().Elem()))
 			}
+			rv = rv.Elem()
+		}
+
+		if !isDynamicType(rv.Type()) {
+			setErr = errors.New("cannot decode into non empty interface " + rv.Type().String())
+			break
+		}
+
+		val, err := dynamicValue(value, path)
+		if err != nil {
+			setErr = err
+			break
+		}
+
+		if val == nil {
+			rv.Set(reflect.Zero(rv.Type()))
+		} else {
+			rv.Set(reflect.ValueOf(val))
 		}
 
 	default:
-		setErr = errors.New(path + ": unknown type " + rk.String())
+		setErr = errors.New("unknown type " + rk.String())
 	}
 
 	if setErr != nil {
-		return fmt.Errorf("%s: %w", path, setErr)
+		return d.fail(path, rv, value, setErr)
 	}
 
 	return nil
 }
 
 func (d *Decoder) block(node *BlockNode, rv reflect.Value, path string) error {
-	pc := 0
-	d.paramCounter = pc
+	d.line = node.Token.Line
+	d.pos = node.Token.Pos
 
 	// track block assignments to make sure we aren't trying to re assign
-	if rv.Kind() != reflect.Slice {
+	if rv.Kind() != reflect.Slice && (rv.Kind() != reflect.Map || isDynamicBlockTarget(rv.Type())) {
 		if _, ok := d.blockMap[rv][node.TokenLiteral()]; ok {
-			return fmt.Errorf("multiple \"%s\" blocks found for field that is not a slice", node.TokenLiteral())
+			return d.fail(path, rv, node, &DecodeError{
+				Line:     d.line,
+				Pos:      d.pos,
+				Expected: rv.Kind(),
+				Found:    node.Token.Type,
+				Err:      fmt.Errorf("multiple \"%s\" blocks found for field that is not a slice", node.TokenLiteral()),
+			})
 		}
 
 		if _, ok := d.blockMap[rv]; !ok {
@@ -193,27 +591,134 @@ func (d *Decoder) block(node *BlockNode, rv reflect.Value, path string) error {
 		d.blockMap[rv][node.TokenLiteral()] = struct{}{}
 	}
 
-	var originalTarget reflect.Value
-	if rv.Kind() == reflect.Slice {
-		newTgt := reflect.New(rv.Type().Elem()).Elem()
+	var (
+		originalTarget reflect.Value
+		entry          reflect.Value
+		mapKey         reflect.Value
+		params         = node.Parameters
+	)
+
+	if u, ok := unmarshaler(rv); ok {
+		return d.unmarshalBlock(u, node, params, rv, path)
+	}
+
+	if isDynamicBlockTarget(rv.Type()) {
+		return d.dynamicBlock(node, rv, path)
+	}
+
+	// dotted keys can be used to set the entries of a map eg labels.team = "ops"
+	if node.dotted && rv.Kind() == reflect.Map && !isBlockType(rv.Type().Elem()) {
+		return d.assignValue(dottedMap(node), rv, path)
+	}
+
+	if !isBlockTarget(rv.Type()) {
+		return d.fail(path, rv, node, errors.New("cannot decode block into "+rv.Type().String()))
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice:
 		originalTarget = rv
-		rv = newTgt
-	} else if rv.Kind() == reflect.Pointer && rv.IsNil() {
-		rv.Set(reflect.New(rv.Type().Elem()))
+		entry, rv = newBlockEntry(rv.Type().Elem())
+
+	case reflect.Map:
+		// map blocks are keyed by their first param
+		if len(params) == 0 {
+			return d.fail(path, rv, node, &DecodeError{
+				Line:     d.line,
+				Pos:      d.pos,
+				Expected: rv.Kind(),
+				Found:    node.Token.Type,
+				Err:      fmt.Errorf("\"%s\" blocks must have a param to use as the map key", node.TokenLiteral()),
+			})
+		}
+
+		if rv.Type().Key().Kind() != reflect.String {
+			return d.fail(path, rv, node, errors.New("Map keys must be a string"))
+		}
+
+		if !isBlockType(rv.Type().Elem()) {
+			return d.fail(path, rv, node, errors.New("cannot decode block into "+rv.Type().String()))
+		}
+
+		mapKey = reflect.ValueOf(params[0].Literal).Convert(rv.Type().Key())
+		path += "[" + strconv.Quote(params[0].Literal) + "]"
+		params = params[1:]
+
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMap(rv.Type()))
+		}
+
+		if rv.MapIndex(mapKey).IsValid() {
+			return d.fail(path, rv, node, &DecodeError{
+				Line:     d.line,
+				Pos:      d.pos,
+				Expected: rv.Kind(),
+				Found:    node.Token.Type,
+				Err:      fmt.Errorf("multiple \"%s\" blocks found with the same key", node.TokenLiteral()),
+			})
+		}
+
+		originalTarget = rv
+		entry, rv = newBlockEntry(rv.Type().Elem())
+
+	case reflect.Pointer:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
 		rv = rv.Elem()
 	}
 
+	if u, ok := unmarshaler(rv); ok {
+		if err := d.unmarshalBlock(u, node, params, rv, path); err != nil {
+			return err
+		}
+	} else if err := d.blockBody(node, params, rv, path); err != nil {
+		return err
+	}
+
+	switch originalTarget.Kind() {
+	case reflect.Slice:
+		originalTarget.Set(reflect.Append(originalTarget, entry))
+	case reflect.Map:
+		originalTarget.SetMapIndex(mapKey, entry)
+	}
+
+	return nil
+}
+
+// dottedMap converts a block expanded from dotted keys into the equivalent map node
+// any nested blocks become nested maps
+func dottedMap(node *BlockNode) *MapNode {
+	m := &MapNode{Token: node.Token, Elements: make(map[Node]Node, len(node.Body.Nodes))}
+
+	for _, n := range node.Body.Nodes {
+		switch n := n.(type) {
+		case *AssignNode:
+			m.Elements[n.Name] = n.Value
+		case *BlockNode:
+			m.Elements[&Identifier{Token: n.Token, Value: n.Token.Literal}] = dottedMap(n)
+		}
+	}
+
+	return m
+}
+
+// blockBody decodes the params and body of the block into the target struct
+func (d *Decoder) blockBody(node *BlockNode, params []Token, rv reflect.Value, path string) error {
+	pc := 0
+	d.paramCounter = pc
+
 	// params
-	for _, param := range node.Parameters {
+	for _, param := range params {
 		d.line = param.Line
-		d.line = param.Pos
+		d.pos = param.Pos
 
 		v, _, _, err := d.findTargetField(&Identifier{Value: ".param"}, rv)
 		if err != nil {
 			if errors.Is(errFieldNotFound, err) {
 				continue
 			}
-			return err
+			return d.withLine(err)
 		}
 
 		rv := *v
@@ -221,7 +726,15 @@ func (d *Decoder) block(node *BlockNode, rv reflect.Value, path string) error {
 		d.paramCounter = pc
 
 		if rv.Kind() != reflect.String {
-			return errors.New(path + ": .param fields must be a string")
+			if err := d.fail(path, rv, nil, &DecodeError{
+				Line:  d.line,
+				Pos:   d.pos,
+				Found: param.Type,
+				Err:   errors.New(".param fields must be a string"),
+			}); err != nil {
+				return err
+			}
+			continue
 		}
 
 		rv.SetString(param.Literal)
@@ -234,13 +747,176 @@ func (d *Decoder) block(node *BlockNode, rv reflect.Value, path string) error {
 		}
 	}
 
-	if originalTarget.Kind() == reflect.Slice {
-		originalTarget.Set(reflect.Append(originalTarget, rv))
+	if err := d.unsetFields(node.Body.Nodes, rv, path, node.Token.Line); err != nil {
+		return d.withLine(err)
+	}
+
+	if err := d.comments(node.Body.Nodes, node.Body.Comments, rv, path); err != nil {
+		return d.withLine(err)
+	}
+
+	return nil
+}
+
+// unmarshalBlock passes the block to the targets Unmarshaler
+// the block is passed with the given params so that map keys can be left out
+func (d *Decoder) unmarshalBlock(u Unmarshaler, node *BlockNode, params []Token, rv reflect.Value, path string) error {
+	block := *node
+	block.Parameters = params
+
+	if err := u.UnmarshalICL(&block); err != nil {
+		return d.fail(path, rv, node, &DecodeError{
+			Line:     node.Token.Line,
+			Pos:      node.Token.Pos,
+			Expected: baseKind(rv),
+			Found:    node.Token.Type,
+			Err:      err,
+		})
+	}
+
+	return nil
+}
+
+// assignTime parses duration strings into time.Duration values and times into time.Time values
+// times will be parsed using the layout from the field tag, falling back to RFC 3339
+func (d *Decoder) assignTime(s string, rv reflect.Value) error {
+	var val reflect.Value
+
+	if isType(rv.Type(), durationType) {
+		dur, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+
+		val = reflect.ValueOf(dur)
+	} else {
+		layout := d.layout
+		if layout == "" {
+			layout = time.RFC3339
+		}
+
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			return err
+		}
+
+		val = reflect.ValueOf(t)
+	}
+
+	if rv.Kind() == reflect.Pointer {
+		ptr := reflect.New(rv.Type().Elem())
+		ptr.Elem().Set(val)
+		val = ptr
+	}
+
+	rv.Set(val)
+
+	return nil
+}
+
+// byteSizeValue parses the size from a string, env var or number node
+// ok will be false for any other node or for numbers that are not a valid size so they can be decoded as normal
+func byteSizeValue(value Node) (size ByteSize, ok bool, err error) {
+	if str, ok := stringValue(value); ok {
+		size, err := ParseByteSize(str)
+		return size, true, err
+	}
+
+	if n, ok := value.(*NumberNode); ok {
+		size, err := ParseByteSize(n.Value)
+		return size, err == nil, nil
+	}
+
+	return 0, false, nil
+}
+
+// assignByteSize sets the size on an int or uint value making sure it fits within the type
+func assignByteSize(size ByteSize, rv reflect.Value) error {
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.OverflowInt(int64(size)) {
+			return fmt.Errorf("byte size %s overflows %s", size, rv.Type())
+		}
+
+		rv.SetInt(int64(size))
+	default:
+		if size < 0 || rv.OverflowUint(uint64(size)) {
+			return fmt.Errorf("byte size %s overflows %s", size, rv.Type())
+		}
+
+		rv.SetUint(uint64(size))
+	}
+
+	return nil
+}
+
+// dynamicBlock decodes a block into an any, map[string]any or []any target
+func (d *Decoder) dynamicBlock(node *BlockNode, rv reflect.Value, path string) error {
+	val, err := dynamicBlock(node, path)
+	if err != nil {
+		return d.fail(path, rv, node, err)
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice:
+		rv.Set(reflect.Append(rv, reflect.ValueOf(val)))
+	case reflect.Map:
+		rv.Set(reflect.ValueOf(val).Convert(rv.Type()))
+	default:
+		rv.Set(reflect.ValueOf(val))
 	}
 
 	return nil
 }
 
+// assignElement decodes a slice or map entry into a new value of the given type
+// ok will be false if the entry failed to decode while the decoder is collecting errors
+func (d *Decoder) assignElement(value Node, rt reflect.Type, path string) (elem reflect.Value, ok bool, err error) {
+	errCount := len(d.errs)
+	elem = reflect.New(rt).Elem()
+
+	if err := d.assignValue(value, elem, path); err != nil {
+		return elem, false, err
+	}
+
+	return elem, len(d.errs) == errCount, nil
+}
+
+// isBlockTarget checks if a block can be decoded into the type, blocks need a struct, a type that implements
+// Unmarshaler or a pointer, slice or map of one of them
+func isBlockTarget(rt reflect.Type) bool {
+	switch rt.Kind() {
+	case reflect.Slice, reflect.Map:
+		rt = rt.Elem()
+	}
+
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+
+	return rt.Kind() == reflect.Struct || reflect.PointerTo(rt).Implements(unmarshalerType)
+}
+
+// newBlockEntry creates a new value to decode a block into for use as a slice or map entry
+// the entry is the value to be stored in the collection and target is the struct that the block is decoded into
+func newBlockEntry(rt reflect.Type) (entry reflect.Value, target reflect.Value) {
+	if rt.Kind() == reflect.Pointer {
+		entry = reflect.New(rt.Elem())
+		return entry, entry.Elem()
+	}
+
+	entry = reflect.New(rt).Elem()
+	return entry, entry
+}
+
 func (d *Decoder) node(node Node, target reflect.Value, path string) error {
 	var err error
 
@@ -248,31 +924,370 @@ func (d *Decoder) node(node Node, target reflect.Value, path string) error {
 	case *AssignNode:
 		err = d.assign(n, target, path)
 	case *BlockNode:
-		v, _, tag, err := d.findTargetField(&Identifier{Value: n.Token.Literal}, target)
-		if err != nil {
-			if errors.Is(errFieldNotFound, err) {
+		v, _, tag, findErr := d.findTargetField(&Identifier{Value: n.Token.Literal}, target)
+		if findErr != nil {
+			if errors.Is(errFieldNotFound, findErr) {
+				d.unknownField(path+"."+n.Token.Literal, n.Token)
 				return nil
 			}
-			return err
+			err = findErr
+			break
 		}
 
-		err = d.block(n, *v, path+"."+tag.key)
+		// errors returned from the block already have their line info attached
+		return d.block(n, *v, path+"."+tag.key)
 	default:
 		// NB to keep icl as fault tollerent as possible any other node types in the ast will be ignored
 	}
 
 	if r := d.recover; r != nil {
 		d.recover = nil
-		err = r
+		err = d.record(r)
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	// decode errors already contain their line info
+	if _, ok := err.(*DecodeError); ok {
+		return err
+	}
+
+	return d.withLine(err)
+}
+
+// fail converts the error into a *DecodeError for the field at the given path
+// the expected kind and found token type are taken from the target and node when the error does not already set them
+// if the decoder is collecting errors it will be recorded and nil returned
+func (d *Decoder) fail(path string, rv reflect.Value, node Node, err error) error {
+	de, ok := err.(*DecodeError)
+	if !ok {
+		de = &DecodeError{Line: d.line, Pos: d.pos, Err: err}
+	}
+
+	if de.Path == "" {
+		de.Path = path
+	}
+
+	if de.Expected == reflect.Invalid && rv.IsValid() {
+		de.Expected = rv.Kind()
+		if de.Expected == reflect.Ptr {
+			de.Expected = rv.Type().Elem().Kind()
+		}
+	}
+
+	if de.Found == "" && node != nil {
+		de.Found = node.Tkn().Type
+	}
+
+	return d.record(de)
+}
+
+// record stores the error if the decoder is collecting errors, otherwise it is returned as is
+func (d *Decoder) record(err *DecodeError) error {
+	if err.Excerpt == "" {
+		err.Excerpt = d.excerpt(err.Line, err.Pos)
+	}
+
+	if !d.opts.CollectErrors {
+		return err
+	}
+
+	d.errs = append(d.errs, err)
+
+	return nil
+}
+
+// excerpt renders the line of the document for an error
+// when decoding from a reader only the lines that have not yet been discarded by the lexer are available
+func (d *Decoder) excerpt(line, pos int) string {
+	if d.parser != nil {
+		return d.parser.lex.excerpt(line, pos)
+	}
+
+	return excerpt(d.ast.source, line, pos)
+}
+
+// collectedErrors combines all the errors found by the decoder into a single DecodeErrors value ordered by
+// their position in the document
+func (d *Decoder) collectedErrors() error {
+	errs := d.errs
+
+	for _, f := range d.unknown {
+		errs = append(errs, &DecodeError{
+			Path:    f.Path,
+			Line:    f.Line,
+			Pos:     f.Pos,
+			Err:     errUnknownField,
+			Excerpt: d.excerpt(f.Line, f.Pos),
+		})
+	}
+
+	for _, f := range d.missing {
+		errs = append(errs, &DecodeError{Path: f.Path, Line: f.Line, Err: errMissingField})
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	// errors without a line, such as fields missing from the root of the document, go after the rest
+	sort.SliceStable(errs, func(i, j int) bool {
+		if (errs[i].Line == 0) != (errs[j].Line == 0) {
+			return errs[j].Line == 0
+		}
+
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+
+		return errs[i].Pos < errs[j].Pos
+	})
+
+	return errs
+}
+
+// withLine attaches the current line and position of the decoder to the error
+func (d *Decoder) withLine(err error) error {
+	return fmt.Errorf("%w\nline(%d) pos(%d)", err, d.line, d.pos)
+}
+
+// unknownField records an assignment or block that has no matching struct field
+// if unknown fields are allowed then it will be ignored
+func (d *Decoder) unknownField(path string, tkn Token) {
+	if !d.opts.DisallowUnknownFields {
+		return
+	}
+
+	d.unknown = append(d.unknown, UnknownField{Path: path, Line: tkn.Line, Pos: tkn.Pos})
+}
+
+// unsetFields handles the fields on the target struct that have not been set by the document
+// required fields are recorded as missing and fields with a default value have it assigned
+//
+// struct fields that have no block in the document are checked the same way
+func (d *Decoder) unsetFields(nodes []Node, target reflect.Value, path string, line int) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s: %v", path, r)
+		}
+	}()
+
+	info, err := cachedFields(target.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, f := range info.fields {
+		rf := f.sf
+		tag := f.tag
+
+		if tag.isParam || tag.isComments || findNode(nodes, tag.key) != nil {
+			continue
+		}
+
+		if tag.required {
+			d.missing = append(d.missing, MissingField{Path: path + "." + tag.key, Block: path, Line: line})
+		}
+
+		if !tag.hasDefault {
+			if isBlockType(rf.Type) && rf.Type.Kind() == reflect.Struct {
+				rv, ok := fieldByIndex(target, f.index, false)
+				if !ok {
+					continue
+				}
+
+				if err := d.unsetFields(nil, rv, path+"."+tag.key, line); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		rv, ok := fieldByIndex(target, f.index, true)
+		if !ok {
+			return fmt.Errorf("%s.%s: cannot set embedded pointer to unexported struct for field %s", path, tag.key, rf.Name)
+		}
+
+		node, err := defaultNode(tag.def, rf.Type)
+		if err != nil {
+			return fmt.Errorf("%s.%s: invalid default value: %w", path, tag.key, err)
+		}
+
+		// a bad default is an issue with the struct rather than the document so it should never be collected
+		dd := NewDecoder(Ast{}, rv)
+		dd.layout = tag.layout
+		dd.bytes = tag.bytes
+		if err := dd.assignValue(node, rv, path+"."+tag.key); err != nil {
+			var de *DecodeError
+			if errors.As(err, &de) {
+				err = de.Err
+			}
+
+			return fmt.Errorf("%s.%s: invalid default value: %w", path, tag.key, err)
+		}
+	}
+
+	return nil
+}
+
+// findNode finds the first assignment or block in the list of nodes with the given key
+func findNode(nodes []Node, key string) commented {
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case *AssignNode:
+			if n.Name.Value == key {
+				return n
+			}
+		case *BlockNode:
+			if n.Token.Literal == key {
+				return n
+			}
+		}
+	}
+
+	return nil
+}
+
+// defaultNode converts the default value from a struct tag into a node so it can be decoded the same way as
+// a value in the document
+//
+// string fields use the raw default value, all other types expect the default to be a valid icl value
+func defaultNode(def string, rt reflect.Type) (Node, error) {
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+
+	if rt.Kind() == reflect.String || rt == durationType || isTextType(rt) {
+		return &StringNode{Token: Token{Type: TknString, Literal: def}, Value: def}, nil
+	}
+
+	p := NewParser(NewLexer(def))
+	node := p.parseExpression()
+
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+
+	if !p.peekTokenIs(TknEof) {
+		return nil, fmt.Errorf("unexpected %s after value", p.peekToken.Type)
 	}
 
+	return node, nil
+}
+
+// comments fills out any .comments fields on the target struct
+//
+// []string fields are given the comments directly before the assignment/block of the next field in the struct,
+// if there is no following field then they are given the comments at the end of the document/block
+//
+// map[int]string fields are given every comment in the document/block keyed by the line they are found on
+func (d *Decoder) comments(nodes []Node, dangling []Token, target reflect.Value, path string) error {
+	info, err := cachedFields(target.Type())
 	if err != nil {
-		return fmt.Errorf("%w\nline(%d) pos(%d)", err, d.line, d.pos)
+		return err
+	}
+
+	for i, f := range info.fields {
+		rf := f.sf
+		if !f.tag.isComments {
+			continue
+		}
+
+		rv, ok := fieldByIndex(target, f.index, true)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case rf.Type == reflect.TypeOf([]string{}):
+			key, ok := nextFieldKey(info.fields, i)
+
+			var comments []Token
+			if ok {
+				comments = leadingComments(nodes, key)
+			} else {
+				comments = trailingComments(nodes, dangling)
+			}
+
+			if len(comments) == 0 {
+				continue
+			}
+
+			texts := make([]string, 0, len(comments))
+			for _, comment := range comments {
+				texts = append(texts, commentText(comment))
+			}
+
+			rv.Set(reflect.ValueOf(texts))
+
+		case rf.Type == reflect.TypeOf(map[int]string{}):
+			var comments []Token
+			for _, node := range nodes {
+				if n, ok := node.(commented); ok {
+					comments = append(comments, n.comments().all()...)
+				}
+			}
+			comments = append(comments, dangling...)
+
+			if len(comments) == 0 {
+				continue
+			}
+
+			if rv.IsNil() {
+				rv.Set(reflect.MakeMap(rf.Type))
+			}
+
+			for _, comment := range comments {
+				rv.SetMapIndex(reflect.ValueOf(comment.Line), reflect.ValueOf(commentText(comment)))
+			}
+
+		default:
+			return errors.New(path + ": .comments fields must be of type []string or map[int]string")
+		}
 	}
 
 	return nil
 }
 
+// nextFieldKey finds the icl key of the first field after the given index that maps to a document node
+func nextFieldKey(fields []field, index int) (string, bool) {
+	for _, f := range fields[index+1:] {
+		if f.tag.isParam || f.tag.isComments {
+			continue
+		}
+
+		return f.tag.key, true
+	}
+
+	return "", false
+}
+
+// leadingComments finds the leading comments of the first node with the given key
+func leadingComments(nodes []Node, key string) []Token {
+	n := findNode(nodes, key)
+	if n == nil {
+		return nil
+	}
+
+	return n.comments().Leading
+}
+
+// trailingComments finds the comments found after the final node
+func trailingComments(nodes []Node, dangling []Token) []Token {
+	if len(nodes) == 0 {
+		return dangling
+	}
+
+	n, ok := nodes[len(nodes)-1].(commented)
+	if !ok {
+		return dangling
+	}
+
+	return slices.Concat(n.comments().Trailing, dangling)
+}
+
 func (d *Decoder) findTargetField(
 	ident *Identifier,
 	target reflect.Value,
@@ -282,42 +1297,47 @@ func (d *Decoder) findTargetField(
 	*tags,
 	error,
 ) {
-	var paramCounter int
-	for i := 0; i < target.NumField(); i++ {
-		// TODO: this needs a cache so i don't have to keep parsing the struct fields each time
-
-		rv := target.Field(i)
-		rf := target.Type().Field(i)
-
-		tagString := rf.Tag.Get(`icl`)
-		if tagString == "" {
-			continue
-		}
+	info, err := cachedFields(target.Type())
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-		tag, err := parseTags(tagString)
-		if err != nil {
-			return nil, nil, nil, err
+	var (
+		i  int
+		ok bool
+	)
+	if ident.Value == ".param" {
+		if ok = d.paramCounter < len(info.params); ok {
+			i = info.params[d.paramCounter]
 		}
+	} else {
+		i, ok = info.byKey[ident.Value]
+	}
 
-		if tag.key != ident.Value && ident.Value != ".param" {
-			continue
-		}
+	if ok {
+		f := &info.fields[i]
 
-		if tag.isParam && paramCounter < d.paramCounter {
-			paramCounter++
-			continue
+		rv, ok := fieldByIndex(target, f.index, true)
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("cannot set embedded pointer to unexported struct for field %s", f.sf.Name)
 		}
 
-		return &rv, &rf, tag, nil
+		return &rv, &f.sf, f.tag, nil
 	}
 
 	return nil, nil, nil, errFieldNotFound
 }
 
-func (d *Decoder) assignPrimitiveNode(node Node, rv reflect.Value, isSlice bool) error {
+func (d *Decoder) assignPrimitiveNode(node Node, rv reflect.Value) (err error) {
 	d.line = node.Tkn().Line
 	d.pos = node.Tkn().Pos
 
+	defer func() {
+		if err != nil {
+			err = &DecodeError{Line: d.line, Pos: d.pos, Expected: baseKind(rv), Found: node.Tkn().Type, Err: err}
+		}
+	}()
+
 	switch v := node.(type) {
 	case *EnvarNode:
 		val := os.Getenv(v.Identifier.Value)
@@ -327,36 +1347,36 @@ func (d *Decoder) assignPrimitiveNode(node Node, rv reflect.Value, isSlice bool)
 		}
 		switch rk {
 		case reflect.String:
-			assignReflectValue(rv, val, false)
+			assignReflectValue(rv, val)
 		case reflect.Bool:
-			assignReflectValue(rv, val == "true", false)
+			assignReflectValue(rv, val == "true")
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			val, err := parseIntKind(val, rk)
 			if err != nil {
 				return err
 			}
-			assignReflectValue(rv, val, false)
+			assignReflectValue(rv, val)
 		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 			val, err := parseUintKind(val, rk)
 			if err != nil {
 				return err
 			}
-			assignReflectValue(rv, val, false)
+			assignReflectValue(rv, val)
 		case reflect.Float32, reflect.Float64:
 			bs := 32
 			if rk == reflect.Float64 {
 				bs = 64
 			}
 
-			v, err := strconv.ParseFloat(val, bs)
+			v, err := parseFloat(val, bs)
 			if err != nil {
 				return err
 			}
 
 			if rk == reflect.Float32 {
-				assignReflectValue(rv, float32(v), false)
+				assignReflectValue(rv, float32(v))
 			} else {
-				assignReflectValue(rv, v, false)
+				assignReflectValue(rv, v)
 			}
 		}
 	case *NullNode:
@@ -364,17 +1384,17 @@ func (d *Decoder) assignPrimitiveNode(node Node, rv reflect.Value, isSlice bool)
 			return fmt.Errorf("invalid %v type null", baseKind(rv))
 		}
 	case *StringNode:
-		if !checkReflectKind(rv, reflect.String, isSlice) {
+		if !checkReflectKind(rv, reflect.String) {
 			return fmt.Errorf("invalid %v type string", baseKind(rv))
 		}
 
-		assignReflectValue(rv, v.Value, isSlice)
+		assignReflectValue(rv, v.Value)
 	case *BooleanNode:
-		if !checkReflectKind(rv, reflect.Bool, isSlice) {
+		if !checkReflectKind(rv, reflect.Bool) {
 			return fmt.Errorf("invalid %v type bool", baseKind(rv))
 		}
 
-		assignReflectValue(rv, v.Value, isSlice)
+		assignReflectValue(rv, v.Value)
 	case *NumberNode:
 		rk := baseKind(rv)
 
@@ -385,29 +1405,29 @@ func (d *Decoder) assignPrimitiveNode(node Node, rv reflect.Value, isSlice bool)
 				return err
 			}
 
-			assignReflectValue(rv, val, isSlice)
+			assignReflectValue(rv, val)
 		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 			val, err := parseUintKind(v.Value, rk)
 			if err != nil {
 				return err
 			}
 
-			assignReflectValue(rv, val, isSlice)
+			assignReflectValue(rv, val)
 		case reflect.Float32, reflect.Float64:
 			bs := 32
 			if rk == reflect.Float64 {
 				bs = 64
 			}
 
-			val, err := strconv.ParseFloat(v.Value, bs)
+			val, err := parseFloat(v.Value, bs)
 			if err != nil {
 				return err
 			}
 
 			if rk == reflect.Float32 {
-				assignReflectValue(rv, float32(val), isSlice)
+				assignReflectValue(rv, float32(val))
 			} else {
-				assignReflectValue(rv, val, isSlice)
+				assignReflectValue(rv, val)
 			}
 		default:
 			return errors.New("invalid type " + string(v.Tkn().Type) + " : " + rk.String())
@@ -422,18 +1442,18 @@ func (d *Decoder) assignPrimitiveNode(node Node, rv reflect.Value, isSlice bool)
 func parseIntKind(s string, k reflect.Kind) (any, error) {
 	switch k {
 	case reflect.Int8:
-		i, err := strconv.ParseInt(s, 10, 8)
+		i, err := parseInt(s, 8)
 		return int8(i), err
 	case reflect.Int16:
-		i, err := strconv.ParseInt(s, 10, 16)
+		i, err := parseInt(s, 16)
 		return int16(i), err
 	case reflect.Int32:
-		i, err := strconv.ParseInt(s, 10, 32)
+		i, err := parseInt(s, 32)
 		return int32(i), err
 	case reflect.Int64:
-		return strconv.ParseInt(s, 10, 64)
+		return parseInt(s, 64)
 	case reflect.Int:
-		i, err := strconv.ParseInt(s, 10, 64)
+		i, err := parseInt(s, 64)
 		return int(i), err
 	}
 
@@ -443,41 +1463,25 @@ func parseIntKind(s string, k reflect.Kind) (any, error) {
 func parseUintKind(s string, k reflect.Kind) (any, error) {
 	switch k {
 	case reflect.Uint8:
-		i, err := strconv.ParseUint(s, 10, 8)
+		i, err := parseUint(s, 8)
 		return uint8(i), err
 	case reflect.Uint16:
-		i, err := strconv.ParseUint(s, 10, 16)
+		i, err := parseUint(s, 16)
 		return uint16(i), err
 	case reflect.Uint32:
-		i, err := strconv.ParseUint(s, 10, 32)
+		i, err := parseUint(s, 32)
 		return uint32(i), err
 	case reflect.Uint64:
-		return strconv.ParseUint(s, 10, 64)
+		return parseUint(s, 64)
 	case reflect.Uint:
-		i, err := strconv.ParseUint(s, 10, 64)
+		i, err := parseUint(s, 64)
 		return uint(i), err
 	}
 
 	return 0, errors.New("invilid int type")
 }
 
-func checkReflectKind(rv reflect.Value, expected reflect.Kind, isSlice bool) bool {
-	if isSlice {
-		if rv.Kind() != reflect.Slice {
-			return false
-		}
-
-		if rv.Type().Elem().Kind() == expected {
-			return true
-		}
-
-		if rv.Type().Elem().Kind() == reflect.Ptr && rv.Type().Elem().Elem().Kind() == expected {
-			return true
-		}
-
-		return false
-	}
-
+func checkReflectKind(rv reflect.Value, expected reflect.Kind) bool {
 	if rv.Kind() == expected {
 		return true
 	}
@@ -489,20 +1493,19 @@ func checkReflectKind(rv reflect.Value, expected reflect.Kind, isSlice bool) boo
 	return false
 }
 
-func assignReflectValue[T any](rv reflect.Value, val T, isSlice bool) {
-	if isSlice {
-		if rv.Type().Elem().Kind() == reflect.Ptr {
-			rv.Set(reflect.Append(rv, reflect.ValueOf(&val)))
-		} else {
-			rv.Set(reflect.Append(rv, reflect.ValueOf(val)))
-		}
-	} else if rv.Kind() == reflect.Ptr {
-		ptr := reflect.New(reflect.TypeOf(val))
-		ptr.Elem().Set(reflect.ValueOf(val))
-		rv.Set(ptr)
+func assignReflectValue[T any](rv reflect.Value, val T) {
+	rt := rv.Type()
+	elem := reflect.ValueOf(val)
+
+	if rt.Kind() == reflect.Ptr {
+		ptr := reflect.New(rt.Elem())
+		ptr.Elem().Set(elem.Convert(rt.Elem()))
+		elem = ptr
 	} else {
-		rv.Set(reflect.ValueOf(val))
+		elem = elem.Convert(rt)
 	}
+
+	rv.Set(elem)
 }
 
 func baseKind(rv reflect.Value) reflect.Kind {
@@ -0,0 +1,48 @@
+package icl
+
+import (
+	"reflect"
+)
+
+// ParamsKey is the map key that holds the params of a block when it is decoded into a map[string]any
+const ParamsKey = ".params"
+
+// dynamicValue converts an expression node into its natural go type
+//
+// strings and bools map to their go equivalent, numbers become int64 or float64 depending on the literal,
+// null becomes nil, slices become []any and maps become map[string]any
+func dynamicValue(node Node, path string) (any, error) {
+	value, err := nodeValue(node, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return value.Interface(), nil
+}
+
+// dynamicBlock converts a block into a map[string]any, params are stored under the ParamsKey
+func dynamicBlock(node *BlockNode, path string) (map[string]any, error) {
+	value, err := blockValue(node, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return value.Interface().(map[string]any), nil
+}
+
+// isDynamicType checks if the type is an empty interface
+func isDynamicType(rt reflect.Type) bool {
+	return rt.Kind() == reflect.Interface && rt.NumMethod() == 0
+}
+
+// isDynamicBlockTarget checks if a block should be decoded into the type as a map[string]any
+func isDynamicBlockTarget(rt reflect.Type) bool {
+	switch rt.Kind() {
+	case reflect.Map:
+		return rt.Key().Kind() == reflect.String && isDynamicType(rt.Elem())
+	case reflect.Slice:
+		return isDynamicType(rt.Elem())
+	}
+
+	return isDynamicType(rt)
+}
@@ -1,45 +1,76 @@
 package icl
 
 import (
+	"encoding"
 	"errors"
 	"reflect"
+	"sort"
 	"strconv"
+	"time"
 )
 
+// EncodeOptions configures the output of the Encoder
+type EncodeOptions struct {
+	// OmitDefaults leaves out any fields that still hold the default value from their struct tag
+	OmitDefaults bool
+}
+
 // Encoder handles the transation of a strinc into an Ast
 type Encoder struct {
-	ast *Ast
-	rv  reflect.Value
+	ast  *Ast
+	rv   reflect.Value
+	opts EncodeOptions
 }
 
 // NewEncoder creates a new instance of the Encoder struct used to transalate a go struct into an icl Ast
-func NewEncoder(v any) (*Encoder, error) {
+func NewEncoder(v any, opts ...EncodeOptions) (*Encoder, error) {
 	rv := reflect.ValueOf(v)
-	if rv.Kind() != reflect.Struct || (rv.Kind() == reflect.Pointer && rv.Elem().Kind() != reflect.Struct) {
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
 		return nil, errors.New("can only encode struct and *struct values")
 	}
 
-	if rv.Kind() == reflect.Pointer {
-		rv = rv.Elem()
+	e := &Encoder{ast: &Ast{}, rv: rv}
+	if len(opts) > 0 {
+		e.opts = opts[0]
 	}
 
-	return &Encoder{&Ast{}, rv}, nil
+	return e, nil
 }
 
 // Encode runs the encoder logic and returns the resulting Ast
 func (e Encoder) Encode(v any) (*Ast, error) {
-	for i := 0; i < e.rv.NumField(); i++ {
-		value := e.rv.Field(i)
-		rf := e.rv.Type().Field(i)
+	var comments []Token
 
-		tagString := rf.Tag.Get(`icl`)
-		if tagString == "" {
+	info, err := cachedFields(e.rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range info.fields {
+		rf := f.sf
+		tag := f.tag
+
+		value, ok := fieldByIndex(e.rv, f.index, false)
+		if !ok {
 			continue
 		}
 
-		tag, err := parseTags(tagString)
-		if err != nil {
-			return nil, err
+		if tag.isComments {
+			c, err := buildComments(value)
+			if err != nil {
+				return nil, err
+			}
+
+			comments = append(comments, c...)
+			continue
+		}
+
+		if e.omitDefault(tag, rf, value) {
+			continue
 		}
 
 		n, err := e.buildNode(tag, rf, value)
@@ -47,9 +78,15 @@ func (e Encoder) Encode(v any) (*Ast, error) {
 			return nil, err
 		}
 
+		if attachLeadingComments(n, comments) {
+			comments = nil
+		}
+
 		e.ast.Nodes = append(e.ast.Nodes, n)
 	}
 
+	e.ast.Comments = comments
+
 	return e.ast, nil
 }
 
@@ -66,6 +103,48 @@ func (e Encoder) buildNode(tag *tags, rf reflect.StructField, rv reflect.Value)
 		rv = rv.Elem()
 	}
 
+	if rk == reflect.Interface {
+		if rv.IsNil() {
+			return &AssignNode{
+				Name:  &Identifier{Token: Token{Type: TknIdent, Literal: tag.key}, Value: tag.key},
+				Value: &NullNode{},
+			}, nil
+		}
+
+		rv = rv.Elem()
+		rk = rv.Kind()
+	}
+
+	if m, ok := marshaler(rv); ok {
+		return e.buildCustomNode(tag, m)
+	}
+
+	if n, ok := e.buildTimeNode(tag, rv); ok && tag.env == "" {
+		return &AssignNode{
+			Name:  &Identifier{Token: Token{Type: TknIdent, Literal: tag.key}, Value: tag.key},
+			Value: n,
+		}, nil
+	}
+
+	if n, ok := e.buildByteSizeNode(tag, rv); ok && tag.env == "" {
+		return &AssignNode{
+			Name:  &Identifier{Token: Token{Type: TknIdent, Literal: tag.key}, Value: tag.key},
+			Value: n,
+		}, nil
+	}
+
+	if m, ok := textMarshaler(rv); ok && tag.env == "" {
+		v, err := e.buildTextNode(m)
+		if err != nil {
+			return nil, err
+		}
+
+		return &AssignNode{
+			Name:  &Identifier{Token: Token{Type: TknIdent, Literal: tag.key}, Value: tag.key},
+			Value: v,
+		}, nil
+	}
+
 	switch rk {
 	// primitives
 	case reflect.String,
@@ -94,34 +173,18 @@ func (e Encoder) buildNode(tag *tags, rf reflect.StructField, rv reflect.Value)
 			return nil, errors.New("env() macro not allowed on slice field")
 		}
 
-		var elems []Node
-
-		for i := 0; i < rv.Len(); i++ {
-			var (
-				node Node
-				err  error
-			)
-
-			if rv.Type().Elem().Kind() == reflect.Struct {
-				node, err = e.buildStructNode(tag, rv.Index(i))
-			} else {
-				node, err = e.buildPrimitiveNode(tag, rv.Type().Elem().Kind(), rv.Index(i))
-			}
-			if err != nil {
-				return nil, err
-			}
-			elems = append(elems, node)
+		if rv.Type().Elem().Kind() == reflect.Struct && isBlockType(rv.Type().Elem()) {
+			return e.buildStructSlice(tag, rv)
 		}
 
-		if rv.Type().Elem().Kind() == reflect.Struct {
-			return &CollectionNode{
-				Elements: elems,
-			}, nil
+		v, err := e.buildValueNode(tag, rv)
+		if err != nil {
+			return nil, err
 		}
 
 		return &AssignNode{
 			Name:  &Identifier{Token: Token{Type: TknIdent, Literal: tag.key}, Value: tag.key},
-			Value: &SliceNode{Elements: elems},
+			Value: v,
 		}, nil
 
 	case reflect.Struct:
@@ -136,26 +199,284 @@ func (e Encoder) buildNode(tag *tags, rf reflect.StructField, rv reflect.Value)
 			return nil, errors.New("env() macro not allowed on map field")
 		}
 
-		elems := make(map[Node]Node)
+		if isBlockType(rv.Type().Elem()) {
+			return e.buildMapBlocks(tag, rv)
+		}
+
+		v, err := e.buildValueNode(tag, rv)
+		if err != nil {
+			return nil, err
+		}
+
+		return &AssignNode{
+			Name:  &Identifier{Token: Token{Type: TknIdent, Literal: tag.key}, Value: tag.key},
+			Value: v,
+		}, nil
+	}
+
+	return nil, errors.New("cant convert " + rk.String())
+}
+
+// buildValueNode converts a value into an expression node, slices and maps are converted recursively so they can
+// be nested to any depth
+func (e Encoder) buildValueNode(tag *tags, rv reflect.Value) (Node, error) {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return &NullNode{}, nil
+		}
+		rv = rv.Elem()
+	}
+
+	if m, ok := marshaler(rv); ok {
+		node, err := m.MarshalICL()
+		if err != nil {
+			return nil, err
+		}
+
+		switch node.(type) {
+		case nil:
+			return &NullNode{}, nil
+		case *BlockNode, *AssignNode, *CollectionNode:
+			return nil, errors.New("MarshalICL must return a value node for " + tag.key)
+		}
+
+		return node, nil
+	}
+
+	if n, ok := e.buildTimeNode(tag, rv); ok {
+		return n, nil
+	}
+
+	if n, ok := e.buildByteSizeNode(tag, rv); ok {
+		return n, nil
+	}
+
+	if m, ok := textMarshaler(rv); ok {
+		return e.buildTextNode(m)
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice:
+		node := &SliceNode{}
+
+		for i := 0; i < rv.Len(); i++ {
+			elem, err := e.buildValueNode(tag, rv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+
+			node.Elements = append(node.Elements, elem)
+		}
+
+		return node, nil
+
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, errors.New("map keys must be a string")
+		}
+
+		node := &MapNode{Elements: make(map[Node]Node)}
 
 		for _, key := range rv.MapKeys() {
-			n, err := e.buildPrimitiveNode(tag, rv.Type().Elem().Kind(), rv.MapIndex(key))
+			elem, err := e.buildValueNode(tag, rv.MapIndex(key))
 			if err != nil {
 				return nil, err
 			}
 
-			elems[&StringNode{Value: key.Interface().(string)}] = n
+			node.Elements[&StringNode{Value: key.String()}] = elem
 		}
 
-		return &AssignNode{
-			Name: &Identifier{Token: Token{Type: TknIdent, Literal: tag.key}, Value: tag.key},
-			Value: &MapNode{
-				Elements: elems,
-			},
-		}, nil
+		return node, nil
 	}
 
-	return nil, errors.New("cant convert " + rk.String())
+	return e.buildPrimitiveNode(tag, rv.Kind(), rv)
+}
+
+// buildMapBlocks converts a map of structs into a collection of blocks using the map key as the first param
+// blocks are sorted by key to keep the output stable
+func (e Encoder) buildMapBlocks(tag *tags, rv reflect.Value) (Node, error) {
+	if rv.Type().Key().Kind() != reflect.String {
+		return nil, errors.New("map keys must be a string")
+	}
+
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	var elems []Node
+	for _, key := range keys {
+		value := rv.MapIndex(key)
+		if value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+		}
+
+		block, err := e.buildBlockNode(tag, value)
+		if err != nil {
+			return nil, err
+		}
+
+		// empty trailing params are left out so the block reads the same as one written without them
+		params := block.Parameters
+		for len(params) > 0 && params[len(params)-1].Literal == "" {
+			params = params[:len(params)-1]
+		}
+
+		block.Parameters = append(
+			[]Token{{Type: TknString, Literal: key.String()}},
+			params...,
+		)
+
+		elems = append(elems, block)
+	}
+
+	return &CollectionNode{Elements: elems}, nil
+}
+
+// isBlockType checks if the type is a struct or a pointer to a struct that should be represented as a block
+// structs that implement encoding.TextMarshaler or encoding.TextUnmarshaler are treated as strings
+func isBlockType(rt reflect.Type) bool {
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+
+	return rt.Kind() == reflect.Struct && !isTextType(rt)
+}
+
+// buildCustomNode converts a value that implements Marshaler into an assignment or block named by the tag
+func (e Encoder) buildCustomNode(tag *tags, m Marshaler) (Node, error) {
+	node, err := m.MarshalICL()
+	if err != nil {
+		return nil, err
+	}
+
+	switch n := node.(type) {
+	case nil:
+		node = &NullNode{}
+	case *BlockNode:
+		n.Token = Token{Type: TknIdent, Literal: tag.key}
+		return n, nil
+	case *AssignNode, *CollectionNode:
+		return nil, errors.New("MarshalICL must return a value or block node for " + tag.key)
+	}
+
+	return &AssignNode{
+		Name:  &Identifier{Token: Token{Type: TknIdent, Literal: tag.key}, Value: tag.key},
+		Value: node,
+	}, nil
+}
+
+// buildStructSlice converts a slice of structs into a collection of blocks, elements that implement Marshaler and
+// return value nodes are assigned to the tag key as a slice instead
+func (e Encoder) buildStructSlice(tag *tags, rv reflect.Value) (Node, error) {
+	var (
+		blocks []Node
+		values []Node
+	)
+
+	for i := 0; i < rv.Len(); i++ {
+		m, ok := marshaler(rv.Index(i))
+		if !ok {
+			node, err := e.buildStructNode(tag, rv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, node)
+			continue
+		}
+
+		node, err := e.buildCustomNode(tag, m)
+		if err != nil {
+			return nil, err
+		}
+
+		if assign, ok := node.(*AssignNode); ok {
+			values = append(values, assign.Value)
+		} else {
+			blocks = append(blocks, node)
+		}
+	}
+
+	if len(values) == 0 {
+		return &CollectionNode{Elements: blocks}, nil
+	}
+
+	if len(blocks) != 0 {
+		return nil, errors.New("MarshalICL must return the same kind of node for every element of " + tag.key)
+	}
+
+	return &AssignNode{
+		Name:  &Identifier{Token: Token{Type: TknIdent, Literal: tag.key}, Value: tag.key},
+		Value: &SliceNode{Elements: values},
+	}, nil
+}
+
+// buildBlockNode converts a struct value into a block, values that implement Marshaler must return a block
+func (e Encoder) buildBlockNode(tag *tags, rv reflect.Value) (*BlockNode, error) {
+	if m, ok := marshaler(rv); ok {
+		node, err := m.MarshalICL()
+		if err != nil {
+			return nil, err
+		}
+
+		block, ok := node.(*BlockNode)
+		if !ok {
+			return nil, errors.New("MarshalICL must return a block node for " + tag.key)
+		}
+
+		block.Token = Token{Type: TknIdent, Literal: tag.key}
+		return block, nil
+	}
+
+	node, err := e.buildStructNode(tag, rv)
+	if err != nil {
+		return nil, err
+	}
+
+	return node.(*BlockNode), nil
+}
+
+// buildTimeNode converts time.Duration values into a readable duration string and time.Time values into a string
+// using the layout from the tag, ok will be false for any other type or time.Time fields without a layout
+func (e Encoder) buildTimeNode(tag *tags, rv reflect.Value) (node Node, ok bool) {
+	switch {
+	case rv.Type() == durationType:
+		return &StringNode{Value: formatDuration(time.Duration(rv.Int()))}, true
+	case rv.Type() == timeType && tag.layout != "":
+		return &StringNode{Value: rv.Interface().(time.Time).Format(tag.layout)}, true
+	}
+
+	return nil, false
+}
+
+// buildByteSizeNode converts int and uint values from fields with the bytes tag option into a size string using the
+// most readable unit, ByteSize values are handled by their MarshalText method
+func (e Encoder) buildByteSizeNode(tag *tags, rv reflect.Value) (node Node, ok bool) {
+	if !tag.bytes || !isIntegerType(rv.Type()) || rv.Type() == byteSizeType {
+		return nil, false
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &StringNode{Value: ByteSize(rv.Int()).String()}, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &StringNode{Value: formatByteSize(false, rv.Uint())}, true
+	}
+
+	return nil, false
+}
+
+// buildTextNode converts a value that implements encoding.TextMarshaler into a string node
+func (e Encoder) buildTextNode(m encoding.TextMarshaler) (Node, error) {
+	text, err := m.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	return &StringNode{Value: string(text)}, nil
 }
 
 func (e Encoder) buildPrimitiveNode(tag *tags, rk reflect.Kind, rv reflect.Value) (Node, error) {
@@ -173,7 +494,7 @@ func (e Encoder) buildPrimitiveNode(tag *tags, rk reflect.Kind, rv reflect.Value
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		return &NumberNode{Value: strconv.FormatUint(rv.Uint(), 10)}, nil
 	case reflect.Float32, reflect.Float64:
-		return &NumberNode{Value: strconv.FormatFloat(rv.Float(), 'f', tag.precision, 64)}, nil
+		return &NumberNode{Value: formatFloat(rv.Float(), tag.precision)}, nil
 	case reflect.Pointer:
 		return e.buildPrimitiveNode(tag, rv.Elem().Kind(), rv.Elem())
 	}
@@ -187,18 +508,34 @@ func (e Encoder) buildStructNode(tag *tags, rv reflect.Value) (Node, error) {
 		body   []Node
 	)
 
-	for i := 0; i < rv.NumField(); i++ {
-		value := rv.Field(i)
-		field := rv.Type().Field(i)
+	var comments []Token
+
+	info, err := cachedFields(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range info.fields {
+		field := f.sf
+		ftag := f.tag
 
-		tagString := field.Tag.Get(`icl`)
-		if tagString == "" {
+		value, ok := fieldByIndex(rv, f.index, false)
+		if !ok {
 			continue
 		}
 
-		ftag, err := parseTags(tagString)
-		if err != nil {
-			return nil, err
+		if ftag.isComments {
+			c, err := buildComments(value)
+			if err != nil {
+				return nil, err
+			}
+
+			comments = append(comments, c...)
+			continue
+		}
+
+		if e.omitDefault(ftag, field, value) {
+			continue
 		}
 
 		if ftag.isParam {
@@ -216,6 +553,10 @@ func (e Encoder) buildStructNode(tag *tags, rv reflect.Value) (Node, error) {
 			return nil, err
 		}
 
+		if attachLeadingComments(n, comments) {
+			comments = nil
+		}
+
 		body = append(body, n)
 	}
 
@@ -223,7 +564,81 @@ func (e Encoder) buildStructNode(tag *tags, rv reflect.Value) (Node, error) {
 		Token:      Token{Literal: tag.key},
 		Parameters: params,
 		Body: &BlockBodyNode{
-			Nodes: body,
+			Nodes:    body,
+			Comments: comments,
 		},
 	}, nil
 }
+
+// omitDefault checks if the field should be left out of the document due to it holding its default value
+func (e Encoder) omitDefault(tag *tags, rf reflect.StructField, rv reflect.Value) (omit bool) {
+	if !e.opts.OmitDefaults || !tag.hasDefault {
+		return false
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			omit = false
+		}
+	}()
+
+	node, err := defaultNode(tag.def, rf.Type)
+	if err != nil {
+		return false
+	}
+
+	def := reflect.New(rf.Type).Elem()
+	if err := NewDecoder(Ast{}, def).assignValue(node, def, ""); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(def.Interface(), rv.Interface())
+}
+
+// buildComments converts the value of a .comments field into comment tokens
+// map[int]string fields are ordered by their line, the lines are not used to place the comments in the output
+func buildComments(rv reflect.Value) ([]Token, error) {
+	var comments []Token
+
+	switch v := rv.Interface().(type) {
+	case []string:
+		for _, text := range v {
+			comments = append(comments, commentToken(text))
+		}
+	case map[int]string:
+		lines := make([]int, 0, len(v))
+		for line := range v {
+			lines = append(lines, line)
+		}
+		sort.Ints(lines)
+
+		for _, line := range lines {
+			comments = append(comments, commentToken(v[line]))
+		}
+	default:
+		return nil, errors.New(".comments fields must be of type []string or map[int]string")
+	}
+
+	return comments, nil
+}
+
+// attachLeadingComments sets the comments as the leading comments of the node
+// if the node is unable to hold comments then false will be returned
+func attachLeadingComments(n Node, comments []Token) bool {
+	if len(comments) == 0 {
+		return false
+	}
+
+	if c, ok := n.(*CollectionNode); ok && len(c.Elements) > 0 {
+		n = c.Elements[0]
+	}
+
+	c, ok := n.(commented)
+	if !ok {
+		return false
+	}
+
+	c.comments().Leading = append(comments, c.comments().Leading...)
+
+	return true
+}
@@ -0,0 +1,162 @@
+package icl
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// field describes a tagged struct field
+type field struct {
+	// index is the index sequence used to reach the field, embedded struct fields will have more than one entry
+	index []int
+	sf    reflect.StructField
+	tag   *tags
+}
+
+// structInfo is the cached field metadata for a struct type
+type structInfo struct {
+	fields []field
+	// byKey maps the icl key of each field to its position in fields
+	byKey map[string]int
+	// params holds the positions of the .param fields in declaration order
+	params []int
+	err    error
+}
+
+// fieldCache maps each reflect.Type to its *structInfo
+//
+// it is shared between all decoders and encoders so the tags of a type are only parsed once per process
+var fieldCache sync.Map
+
+// cachedFields returns the field metadata for the struct type, parsing it on first use
+func cachedFields(rt reflect.Type) (*structInfo, error) {
+	if info, ok := fieldCache.Load(rt); ok {
+		return info.(*structInfo), info.(*structInfo).err
+	}
+
+	info := &structInfo{byKey: make(map[string]int)}
+	info.fields, info.err = structFields(rt)
+
+	for i, f := range info.fields {
+		switch {
+		case f.tag.isParam:
+			info.params = append(info.params, i)
+		case f.tag.isComments:
+		default:
+			info.byKey[f.tag.key] = i
+		}
+	}
+
+	actual, _ := fieldCache.LoadOrStore(rt, info)
+	return actual.(*structInfo), info.err
+}
+
+// structFields returns the tagged fields of the struct type in declaration order
+//
+// untagged embedded structs have their tagged fields promoted into the parent following the same rules as
+// encoding/json, a field at a shallower depth hides any deeper fields with the same key and fields at the same
+// depth with the same key hide each other
+func structFields(rt reflect.Type) ([]field, error) {
+	type embedded struct {
+		rt    reflect.Type
+		index []int
+	}
+
+	var (
+		fields  []field
+		next    = []embedded{{rt: rt}}
+		visited = make(map[reflect.Type]bool)
+		hidden  = make(map[string]bool)
+	)
+
+	for len(next) > 0 {
+		current := next
+		next = nil
+
+		var found []field
+		for _, e := range current {
+			if visited[e.rt] {
+				continue
+			}
+			visited[e.rt] = true
+
+			for i := 0; i < e.rt.NumField(); i++ {
+				sf := e.rt.Field(i)
+				index := append(slices.Clone(e.index), i)
+				tagString := sf.Tag.Get(`icl`)
+
+				if sf.Anonymous && tagString == "" {
+					ft := sf.Type
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+
+					if ft.Kind() == reflect.Struct {
+						next = append(next, embedded{rt: ft, index: index})
+					}
+					continue
+				}
+
+				if tagString == "" {
+					continue
+				}
+
+				tag, err := parseTags(tagString)
+				if err != nil {
+					return nil, err
+				}
+
+				sf.Index = index
+				found = append(found, field{index: index, sf: sf, tag: tag})
+			}
+		}
+
+		counts := make(map[string]int)
+		for _, f := range found {
+			if !f.tag.isParam && !f.tag.isComments {
+				counts[f.tag.key]++
+			}
+		}
+
+		for _, f := range found {
+			if !f.tag.isParam && !f.tag.isComments && (hidden[f.tag.key] || counts[f.tag.key] > 1) {
+				continue
+			}
+
+			fields = append(fields, f)
+		}
+
+		for key := range counts {
+			hidden[key] = true
+		}
+	}
+
+	slices.SortFunc(fields, func(a, b field) int {
+		return slices.Compare(a.index, b.index)
+	})
+
+	return fields, nil
+}
+
+// fieldByIndex returns the value of the field at the index sequence
+// nil embedded pointers will be allocated if alloc is true, otherwise ok will be false
+func fieldByIndex(rv reflect.Value, index []int, alloc bool) (v reflect.Value, ok bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				if !alloc || !rv.CanSet() {
+					return reflect.Value{}, false
+				}
+
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+
+			rv = rv.Elem()
+		}
+
+		rv = rv.Field(x)
+	}
+
+	return rv, true
+}
@@ -2,33 +2,55 @@ package icl
 
 import (
 	"fmt"
+	"io"
 	"os"
 )
 
 // Parsean icl a byte array into an Ast
+//
+// If the document contains any syntax errors then a *ParseError will be returned along side the partial Ast
 func Parse(data []byte) (*Ast, error) {
-	p := NewParser(newLexer(string(data)))
-
-	return p.Parse(), nil
+	return ParseWithOptions(data, ParseOptions{})
 }
 
 // ParseString an icl string into an Ast
+//
+// If the document contains any syntax errors then a *ParseError will be returned along side the partial Ast
 func ParseString(data string) (*Ast, error) {
-	p := NewParser(newLexer(data))
-
-	return p.Parse(), nil
+	return ParseStringWithOptions(data, ParseOptions{})
 }
 
 // ParseFile parses the contents of a file into an Ast
 func ParseFile(path string) (*Ast, error) {
+	return ParseFileWithOptions(path, ParseOptions{})
+}
+
+// ParseWithOptions parses an icl byte array into an Ast using the provided options
+//
+// the data is copied once before parsing so the caller is free to reuse it once Parse returns
+func ParseWithOptions(data []byte, opts ParseOptions) (*Ast, error) {
+	p := NewParser(NewLexer(string(data)), opts)
+	a := p.Parse()
+
+	return a, p.Err()
+}
+
+// ParseStringWithOptions parses an icl string into an Ast using the provided options
+func ParseStringWithOptions(data string, opts ParseOptions) (*Ast, error) {
+	p := NewParser(NewLexer(data), opts)
+	a := p.Parse()
+
+	return a, p.Err()
+}
+
+// ParseFileWithOptions parses the contents of a file into an Ast using the provided options
+func ParseFileWithOptions(path string, opts ParseOptions) (*Ast, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
 
-	p := NewParser(newLexer(string(data)))
-
-	return p.Parse(), nil
+	return ParseWithOptions(data, opts)
 }
 
 // Marshal marshals a strict value into a byte array
@@ -76,6 +98,28 @@ func MarshalFile(v any, path string) error {
 	return os.WriteFile(path, a.Bytes(), 0644)
 }
 
+// MarshalWithOptions marshals a struct value into a byte array using the provided options
+func MarshalWithOptions(v any, opts EncodeOptions) ([]byte, error) {
+	e, err := NewEncoder(v, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := e.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.Bytes(), nil
+}
+
+// MarshalStringWithOptions marshals a struct value into a string using the provided options
+func MarshalStringWithOptions(v any, opts EncodeOptions) (string, error) {
+	b, err := MarshalWithOptions(v, opts)
+
+	return string(b), err
+}
+
 // UnMarshal unmarshals a byte array value into a struct
 func UnMarshal(data []byte, v any) error {
 	a, err := Parse(data)
@@ -106,6 +150,68 @@ func UnMarshalFile(path string, v any) error {
 	return a.Unmarshal(v)
 }
 
+// UnMarshalReader unmarshals the document read from r into a struct
+// the document is decoded as it is read, see NewDecoderFromReader for details
+func UnMarshalReader(r io.Reader, v any) error {
+	return NewDecoderFromReader(r).Decode(v)
+}
+
+// UnMarshalWithOptions unmarshals a byte array value into a struct using the provided options
+func UnMarshalWithOptions(data []byte, v any, opts DecodeOptions) error {
+	a, err := Parse(data)
+	if err != nil {
+		return err
+	}
+
+	return a.UnmarshalWithOptions(v, opts)
+}
+
+// UnMarshalStringWithOptions unmarshals a string value into a struct using the provided options
+func UnMarshalStringWithOptions(s string, v any, opts DecodeOptions) error {
+	a, err := ParseString(s)
+	if err != nil {
+		return err
+	}
+
+	return a.UnmarshalWithOptions(v, opts)
+}
+
+// UnMarshalFileWithOptions unmarshals a file path value directly into a struct using the provided options
+func UnMarshalFileWithOptions(path string, v any, opts DecodeOptions) error {
+	a, err := ParseFile(path)
+	if err != nil {
+		return err
+	}
+
+	return a.UnmarshalWithOptions(v, opts)
+}
+
+// UnMarshalReaderWithOptions unmarshals the document read from r into a struct using the provided options
+func UnMarshalReaderWithOptions(r io.Reader, v any, opts DecodeOptions) error {
+	return NewDecoderFromReader(r, opts).Decode(v)
+}
+
+// UnmarshalValue parses a byte array into a generic Value tree without the need for a target struct
+func UnmarshalValue(data []byte) (*Value, error) {
+	a, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.Value()
+}
+
+// UnmarshalMap parses a byte array into a map[string]any without the need for a target struct
+// see Value.Interface for details on how the document is converted
+func UnmarshalMap(data []byte) (map[string]any, error) {
+	v, err := UnmarshalValue(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.Interface().(map[string]any), nil
+}
+
 // UnmarshalVersion takes a map of possible version targets and unmarshels the document int the appropriate one
 // If no appropriate target is found then nothing will be unmarshaled
 func UnmarshalVersion(data []byte, versions map[int]any) (int, any, error) {
@@ -1,11 +1,33 @@
 package icl
 
 import (
-	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
 )
 
+// readerChunkSize is the number of bytes read from the reader at a time
+const readerChunkSize = 4096
+
 type Lexer struct {
 	input string
+	// r is the source of the input when lexing from a reader, it is set to nil once the reader is exhausted
+	r io.Reader
+	// streaming is set when lexing from a reader, input is then a view over window rather than the whole document
+	streaming bool
+	// window holds the part of the document that is still needed, it is compacted in place as the lexer moves on
+	window []byte
+	// err is the first error returned by r
+	err error
+	// firstLine is the line number of the first line held in input
+	firstLine int
+	// firstCol is the number of chars from the first line that have been dropped from the window
+	firstCol int
 	// current position of input (position of char)
 	pos int
 	// current reading pos (char + 1)
@@ -13,57 +35,133 @@ type Lexer struct {
 	// current char under exam
 	char byte
 
-	// line of the input
+	// line of the input (1 based)
 	line int
-	// cursor pos on current line
-	linePos int
+	// position of the first char on the current line
+	lineStart int
+	// lineCol is the number of chars on the current line before lineStart, it is only set when the start of a very
+	// long line has been dropped from the window
+	lineCol int
+	// multiByte is set once a multi byte char has been read on the current line
+	multiByte bool
+
+	// line and position the current token starts at
+	tokenLine  int
+	tokenStart int
+	// tokenPos is the 1 based column of the current token counted in chars
+	tokenPos int
+
+	// line, line start and start of the last token returned, the window is never compacted past the line start so
+	// the parser can still render excerpts for its current token
+	keepLine  int
+	keepStart int
+	keepToken int
 }
 
-// newLexer creates a new Lexer instance with the provided input string
-func newLexer(input string) *Lexer {
-	l := &Lexer{input: input}
+// NewLexer creates a new Lexer instance with the provided input string
+func NewLexer(input string) *Lexer {
+	l := &Lexer{input: input, line: 1, firstLine: 1}
 	l.readChar()
 
 	return l
 }
 
+// NewLexerFromReader creates a new Lexer instance that reads its input from r as it is needed
+//
+// the input is read in chunks and only the lines around the current token are kept in memory so documents of
+// any size can be lexed
+func NewLexerFromReader(r io.Reader) *Lexer {
+	l := &Lexer{r: r, streaming: true, line: 1, firstLine: 1}
+	l.readChar()
+
+	return l
+}
+
+// Err returns the first error encountered while reading the input, io.EOF is not considered an error
+func (l *Lexer) Err() error {
+	return l.err
+}
+
 func (l *Lexer) NextToken() Token {
 	defer l.readChar()
+	l.compact()
 	l.consumeWhitespace()
 
+	l.tokenLine = l.line
+	l.tokenStart = l.pos
+	l.tokenPos = l.lineCol + l.pos - l.lineStart + 1
+	if l.multiByte {
+		l.tokenPos = l.lineCol + utf8.RuneCountInString(l.input[l.lineStart:min(l.pos, len(l.input))]) + 1
+	}
+	l.keepLine = l.line
+	l.keepStart = l.lineStart
+	l.keepToken = l.pos
+
 	switch l.char {
 	case ',':
-		return l.token(TknComma, string(l.char))
+		return l.token(TknComma, l.input[l.pos:l.readPos])
 	case '(':
-		return l.token(TknLParen, string(l.char))
+		return l.token(TknLParen, l.input[l.pos:l.readPos])
 	case ')':
-		return l.token(TknRParen, string(l.char))
+		return l.token(TknRParen, l.input[l.pos:l.readPos])
 	case '{':
-		return l.token(TknLBrace, string(l.char))
+		return l.token(TknLBrace, l.input[l.pos:l.readPos])
 	case '}':
-		return l.token(TknRBrace, string(l.char))
+		return l.token(TknRBrace, l.input[l.pos:l.readPos])
 	case '[':
-		return l.token(TknLBracket, string(l.char))
+		return l.token(TknLBracket, l.input[l.pos:l.readPos])
 	case ']':
-		return l.token(TknRBracket, string(l.char))
+		return l.token(TknRBracket, l.input[l.pos:l.readPos])
 	case '=':
-		return l.token(TknAssign, string(l.char))
+		return l.token(TknAssign, l.input[l.pos:l.readPos])
 	case '#':
 		return l.token(TknComment, l.readLineComment())
+	case '/':
+		switch l.peekChar() {
+		case '/':
+			return l.token(TknComment, l.readLineComment())
+		case '*':
+			comment, ok := l.readBlockComment()
+			if !ok {
+				return l.token(TknIllegal, l.input[l.tokenStart:l.tokenStart+2])
+			}
+			return l.token(TknComment, comment)
+		}
+
+		return l.token(TknIllegal, l.input[l.pos:l.readPos])
 	case ':':
-		return l.token(TknColon, string(l.char))
+		return l.token(TknColon, l.input[l.pos:l.readPos])
 	case '-':
 		return l.token(TknNumber, l.readNumber())
 	case '"', '\'':
-		str := l.readStringLiteral(l.char)
-		if str == nil {
-			return l.token(TknIllegal, string(l.char))
+		str, ok := l.readStringLiteral(l.char)
+		if !ok {
+			return l.token(TknIllegal, l.input[l.tokenStart:l.tokenStart+1])
 		}
-		return l.token(TknString, *str)
+		return l.token(TknString, str)
+	case '`':
+		str, ok := l.readRawString()
+		if !ok {
+			return l.token(TknIllegal, l.input[l.tokenStart:l.tokenStart+1])
+		}
+		return l.token(TknString, str)
+	case '<':
+		if l.peekChar() != '<' {
+			return l.token(TknIllegal, l.input[l.pos:l.readPos])
+		}
+
+		str, opener, ok := l.readHeredoc()
+		if !ok && opener != "" {
+			return l.token(TknIllegal, opener)
+		}
+		if !ok {
+			return l.token(TknIllegal, l.input[l.tokenStart:l.tokenStart+2])
+		}
+		return l.token(TknString, str)
 	case 0:
-		return Token{Type: TknEof}
+		return l.token(TknEof, "")
 	default:
-		if isIdentChar(l.char) {
+		if r, _ := l.runeAt(l.pos); isIdentChar(r) {
 			ident := l.readIdentifier()
 			return l.token(lookupIdent(ident), ident)
 		}
@@ -71,42 +169,198 @@ func (l *Lexer) NextToken() Token {
 			return l.token(TknNumber, l.readNumber())
 		}
 
-		return l.token(TknIllegal, string(l.char))
+		// multi byte chars are returned as a single illegal token rather than one per byte
+		l.skipRune()
+		return l.token(TknIllegal, l.input[l.tokenStart:l.readPos])
 	}
 }
 
 // New initializes a new token
+// the line and pos of the token are both 1 based, pos is counted in chars rather than bytes
 func (l *Lexer) token(tokenType TokenType, char string) Token {
+	// the window is overwritten when it is compacted so the literal cannot be a view over it
+	if l.streaming {
+		char = strings.Clone(char)
+	}
+
 	return Token{
 		Type:    tokenType,
 		Literal: char,
-		Line:    l.line,
-		Pos:     l.linePos - len(char),
+		Line:    l.tokenLine,
+		Pos:     l.tokenPos,
 	}
 }
 
 // readChar reads the next char in the input string
 func (l *Lexer) readChar() {
-	if l.readPos >= len(l.input) {
+	if l.char == '\n' {
+		l.line++
+		l.lineStart = l.readPos
+		l.lineCol = 0
+		l.multiByte = false
+	}
+
+	if l.readPos >= len(l.input) && !l.fill() {
 		l.char = 0
 	} else {
 		l.char = l.input[l.readPos]
+		if l.char >= utf8.RuneSelf {
+			l.multiByte = true
+		}
 	}
 
 	l.pos = l.readPos
 	l.readPos++
-	l.linePos++
 }
 
 // readChar reads the next char in the input string
 func (l *Lexer) peekChar() byte {
-	if l.readPos >= len(l.input) {
+	if l.readPos >= len(l.input) && !l.fill() {
 		return 0
 	}
 
 	return l.input[l.readPos]
 }
 
+// peekRune decodes the char following the current char
+func (l *Lexer) peekRune() (rune, int) {
+	if l.readPos < len(l.input) && l.input[l.readPos] < utf8.RuneSelf {
+		return rune(l.input[l.readPos]), 1
+	}
+
+	return l.runeAt(l.readPos)
+}
+
+// runeAt decodes the utf-8 char starting at the given position in the input
+// 0 is returned if the position is past the end of the input
+func (l *Lexer) runeAt(pos int) (rune, int) {
+	if pos < len(l.input) && l.input[pos] < utf8.RuneSelf {
+		return rune(l.input[pos]), 1
+	}
+
+	// make sure the whole char has been read in before decoding it
+	for pos <= len(l.input) && !utf8.FullRuneInString(l.input[pos:]) && l.fill() {
+	}
+
+	if pos >= len(l.input) {
+		return 0, 0
+	}
+
+	return utf8.DecodeRuneInString(l.input[pos:])
+}
+
+// skipRune reads past the remaining bytes of a multi byte char leaving the cursor on its final byte
+func (l *Lexer) skipRune() {
+	_, size := l.runeAt(l.pos)
+	for i := 1; i < size; i++ {
+		l.readChar()
+	}
+}
+
+// readRune reads the next char in the input leaving the cursor on its final byte
+func (l *Lexer) readRune() {
+	l.readChar()
+	if l.char >= utf8.RuneSelf {
+		l.skipRune()
+	}
+}
+
+// fill reads the next chunk from the reader into the window
+// false is returned if there is no more input to be read
+func (l *Lexer) fill() bool {
+	for l.r != nil {
+		l.window = slices.Grow(l.window, readerChunkSize)
+
+		n, err := l.r.Read(l.window[len(l.window) : len(l.window)+readerChunkSize])
+		l.window = l.window[:len(l.window)+n]
+
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				l.err = err
+			}
+			l.r = nil
+		}
+
+		if n > 0 {
+			l.input = unsafe.String(unsafe.SliceData(l.window), len(l.window))
+			return true
+		}
+	}
+
+	return false
+}
+
+// compact drops the lines before the last token from the window
+// if the line of the last token is very long then it is dropped up to the start of the token instead so that long
+// lines are never held in memory in full
+//
+// this is a noop unless the lexer is reading from a reader
+func (l *Lexer) compact() {
+	if !l.streaming {
+		return
+	}
+
+	n := l.keepStart
+	if l.keepToken-l.keepStart > readerChunkSize {
+		n = l.keepToken
+	}
+
+	if n <= 0 {
+		return
+	}
+
+	if l.keepLine != l.firstLine {
+		l.firstCol = 0
+	}
+	if n > l.keepStart {
+		l.firstCol += utf8.RuneCountInString(l.input[l.keepStart:n])
+	}
+	l.firstLine = l.keepLine
+
+	// the current line is the one being dropped from so its column offset needs to follow the window
+	if l.lineStart < n {
+		l.lineCol = l.firstCol
+		l.lineStart = n
+	}
+
+	copy(l.window, l.window[n:])
+	l.window = l.window[:len(l.window)-n]
+	l.input = unsafe.String(unsafe.SliceData(l.window), len(l.window))
+
+	l.pos -= n
+	l.readPos -= n
+	l.lineStart -= n
+	l.keepStart = 0
+	l.keepToken = 0
+}
+
+// excerpt renders an excerpt for the given line from the input
+// if the lexer is reading from a reader then only the lines still held in the window can be rendered
+func (l *Lexer) excerpt(line, pos int) string {
+	if line < l.firstLine {
+		return ""
+	}
+
+	// make sure the whole line has been read in before rendering it
+	for lines, from := 0, 0; l.r != nil; from = len(l.input) {
+		lines += strings.Count(l.input[from:], "\n")
+		if lines > line-l.firstLine || !l.fill() {
+			break
+		}
+	}
+
+	// the start of the first line has been dropped so the excerpt starts part way through it
+	if line == l.firstLine && l.firstCol > 0 {
+		if pos <= l.firstCol {
+			return ""
+		}
+
+		return excerptFrom("..."+l.input, l.firstLine, line, pos-l.firstCol+len("..."))
+	}
+
+	return excerptFrom(l.input, l.firstLine, line, pos)
+}
+
 // multiReadChar reads multiple characters as a string
 func (l *Lexer) multiReadChar(n int) string {
 	pos := l.pos
@@ -120,133 +374,375 @@ func (l *Lexer) multiReadChar(n int) string {
 // consumeWhitespace keeps reading characters until the current char is not a valid whitespace character
 func (l *Lexer) consumeWhitespace() {
 	for l.char == ' ' || l.char == '\t' || l.char == '\n' || l.char == '\r' {
-		if l.char == '\n' {
-			l.line++
-			l.linePos = 0
-		}
 		l.readChar()
 	}
 }
 
 // readIdentifier reads a string of consecutive valid identifier characters
+//
+// dots are included as long as they are followed by the start of another identifier so dotted keys such as
+// server.tls.enabled are read as a single identifier
 func (l *Lexer) readIdentifier() string {
 	pos := l.pos
+	l.skipRune()
 
-	for isIdentChar(l.peekChar(), true) {
-		l.readChar()
+	for {
+		r, _ := l.peekRune()
+		if r == '.' {
+			if next, _ := l.runeAt(l.readPos + 1); !isIdentChar(next) {
+				break
+			}
+		} else if !isIdentChar(r, true) {
+			break
+		}
+
+		l.readRune()
 	}
 
 	return l.input[pos:l.readPos]
 }
 
-// readStringLiteral reads a string literal
-// this will only accept double quoted strings and can have double quotes escaped with a \
-func (l *Lexer) readStringLiteral(terminator byte) *string {
-	var buf bytes.Buffer
+// readStringLiteral reads a single or double quoted string literal
+//
+// escape sequences are decoded the same way as go string literals, \' and \" are valid in both types of string and
+// any unknown escape sequence is left in the value as is
+//
+// the value is sliced directly from the input, a copy is only made when it contains escape sequences
+func (l *Lexer) readStringLiteral(terminator byte) (string, bool) {
+	var (
+		buf strings.Builder
+		// start of the string value, skipping the opening quote
+		start = l.pos + 1
+		// start of the input that has not yet been written to buf
+		from    = start
+		escaped bool
+	)
 
 	for {
-		char := l.input[l.pos]
-		peekChar := l.peekChar()
-		// if we dont find a closing " then its an invalid string
-		if peekChar == 0 {
-			return nil
-		}
+		l.readChar()
+
+		switch l.char {
+		case 0:
+			// if we dont find a closing quote then its an invalid string
+			return "", false
+
+		case terminator:
+			if !escaped {
+				return l.input[start:l.pos], true
+			}
+
+			buf.WriteString(l.input[from:l.pos])
+			return buf.String(), true
+
+		case '\\':
+			escStart := l.pos
+			l.readChar()
+			if l.char == 0 {
+				return "", false
+			}
+
+			// short or invalid sequences stop at the first non hex char so they never consume the closing quote
+			for i := escapeLength(l.char); i > 0 && isHexDigit(l.peekChar()); i-- {
+				l.readChar()
+			}
 
-		if char != '\\' || peekChar != '"' {
-			buf.WriteString(string(char))
+			buf.WriteString(l.input[from:escStart])
+			writeEscape(&buf, l.input[escStart:l.readPos], terminator)
+			from = l.readPos
+			escaped = true
 		}
+	}
+}
+
+// escapeLength returns the number of chars that follow the identifying char of an escape sequence
+func escapeLength(char byte) int {
+	switch char {
+	case 'x':
+		return 2
+	case 'u':
+		return 4
+	case 'U':
+		return 8
+	}
 
+	return 0
+}
+
+// writeEscape decodes the escape sequence into the buffer
+// if the sequence is not valid then it is written as is
+func writeEscape(buf *strings.Builder, seq string, terminator byte) {
+	if seq == `\'` || seq == `\"` {
+		buf.WriteByte(seq[1])
+		return
+	}
+
+	value, multibyte, tail, err := strconv.UnquoteChar(seq, terminator)
+	if err != nil || tail != "" {
+		buf.WriteString(seq)
+		return
+	}
+
+	if value < utf8.RuneSelf || !multibyte {
+		buf.WriteByte(byte(value))
+	} else {
+		buf.WriteRune(value)
+	}
+}
+
+// readRawString reads a backtick quoted string, the value is taken as is with no escape sequences
+func (l *Lexer) readRawString() (string, bool) {
+	start := l.pos + 1
+
+	for {
 		l.readChar()
-		if peekChar == terminator && char != '\\' {
-			break
+
+		switch l.char {
+		case 0:
+			return "", false
+		case '`':
+			return l.input[start:l.pos], true
 		}
 	}
+}
 
-	// skip final "
-	if l.peekChar() == terminator {
+// readHeredoc reads a heredoc string starting from the first < of the opening marker
+//
+// the value is every line between the opening and closing marker including the final newline, if the marker is
+// prefixed with a - (<<-EOT) then the indentation shared by every line will be stripped. the closing marker may be
+// surrounded by whitespace
+//
+// if the closing marker is never found then the opening marker (<<EOT) is returned so that it can be reported
+func (l *Lexer) readHeredoc() (str string, opener string, ok bool) {
+	// skip <
+	l.readChar()
+
+	strip := l.peekChar() == '-'
+	if strip {
 		l.readChar()
 	}
 
-	// dont include the " on each side in the strings value
-	str := buf.String()[1:]
-	return &str
-}
+	if r, _ := l.peekRune(); !isIdentChar(r) {
+		return "", "", false
+	}
 
-func (l *Lexer) readLineComment() string {
-	var buf bytes.Buffer
+	l.readChar()
+	marker := l.readIdentifier()
+	opener = l.input[l.tokenStart:l.readPos]
 
+	// nothing else is allowed on the line of the opening marker
+	for l.peekChar() == ' ' || l.peekChar() == '\t' || l.peekChar() == '\r' {
+		l.readChar()
+	}
+
+	if l.peekChar() != '\n' {
+		return "", "", false
+	}
+	l.readChar()
+
+	var lines []string
 	for {
-		char := l.input[l.pos]
-		peekChar := l.peekChar()
+		start := l.readPos
+		for l.peekChar() != '\n' && l.peekChar() != 0 {
+			l.readChar()
+		}
 
-		if peekChar == 0 {
-			break
-		} else if char == '\r' && peekChar != '\n' {
-			break
-		} else if char == '\n' {
+		line := strings.TrimSuffix(l.input[start:l.readPos], "\r")
+		if strings.Trim(line, " \t") == marker {
 			break
 		}
-		buf.WriteString(string(char))
 
+		if l.peekChar() == 0 {
+			return "", opener, false
+		}
+
+		lines = append(lines, line)
 		l.readChar()
 	}
 
-	return buf.String()
+	if len(lines) == 0 {
+		return "", "", true
+	}
+
+	if strip {
+		stripIndent(lines)
+	}
+
+	return strings.Join(lines, "\n") + "\n", "", true
 }
 
-func (l *Lexer) readBlockComment() string {
-	var buf bytes.Buffer
+// stripIndent removes the leading whitespace shared by all the non blank lines
+func stripIndent(lines []string) {
+	indent := -1
+	for _, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
 
-	for {
-		char := l.input[l.pos]
-		peekChar := l.peekChar()
-		if peekChar == 0 {
-			break
+		if n := len(line) - len(trimmed); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+
+	for i, line := range lines {
+		if len(line) < indent {
+			lines[i] = strings.TrimLeft(line, " \t")
+		} else if indent > 0 {
+			lines[i] = line[indent:]
 		}
+	}
+}
 
-		buf.WriteString(string(char))
+// readLineComment reads a comment up to but not including the end of the line
+func (l *Lexer) readLineComment() string {
+	pos := l.pos
+
+	for l.peekChar() != '\n' && l.peekChar() != 0 {
+		l.readChar()
+	}
+
+	return strings.TrimSuffix(l.input[pos:l.readPos], "\r")
+}
 
-		if char == '*' && peekChar == '/' {
+// readBlockComment reads a /* */ comment which can span multiple lines
+//
+// the indentation of the line the comment starts on is stripped from the rest of its lines so the comment keeps its
+// shape when the document is formatted
+func (l *Lexer) readBlockComment() (string, bool) {
+	pos := l.pos
+	line := l.input[l.lineStart:l.pos]
+	indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+
+	// skip /*
+	l.readChar()
+
+	for {
+		l.readChar()
+
+		if l.char == 0 {
+			return "", false
+		}
+
+		if l.char == '*' && l.peekChar() == '/' {
 			l.readChar()
-			buf.WriteString("/")
 			break
 		}
+	}
 
-		l.readChar()
+	comment := l.input[pos:l.readPos]
+	if indent == "" || !strings.Contains(comment, "\n") {
+		return comment, true
+	}
+
+	lines := strings.Split(comment, "\n")
+	for i := 1; i < len(lines); i++ {
+		lines[i] = strings.TrimPrefix(lines[i], indent)
 	}
 
-	return buf.String()
+	return strings.Join(lines, "\n"), true
 }
 
 // readNumber reads a numeric value from the input string
+//
+// along with decimal numbers this will read hex, octal and binary literals, _ separators and exponents, a - followed
+// by a word is also read as a number so -inf can be used as a float value
 func (l *Lexer) readNumber() string {
-	var isFloat bool
 	pos := l.pos
 
-	for isDigit(l.peekChar()) || l.peekChar() == '.' {
-		if l.peekChar() == '.' {
+	if r, _ := l.peekRune(); l.char == '-' && isIdentChar(r) {
+		for r, _ := l.peekRune(); isIdentChar(r, true); r, _ = l.peekRune() {
+			l.readRune()
+		}
+
+		return l.input[pos:l.readPos]
+	}
+
+	var isFloat bool
+	for r, _ := l.peekRune(); isDigit(l.peekChar()) || isIdentChar(r) || r == '.'; r, _ = l.peekRune() {
+		if r == '.' {
 			if isFloat {
 				break
 			}
 			isFloat = true
 		}
 
-		l.readChar()
+		l.readRune()
+
+		// exponents can be signed, e for decimal numbers and p for hex
+		if l.peekChar() == '-' || l.peekChar() == '+' {
+			hex := isHexLiteral(l.input[pos:l.readPos])
+
+			switch {
+			case !hex && (l.char == 'e' || l.char == 'E'),
+				hex && (l.char == 'p' || l.char == 'P'):
+				l.readChar()
+			}
+		}
 	}
 
 	return l.input[pos:l.readPos]
 }
 
-// isIdentChar checks if the provided byte is a valid character for an identifier
-func isIdentChar(char byte, subsequent ...bool) bool {
-	// numbers are allowed in idents but not as the first character
-	if len(subsequent) > 0 && subsequent[0] && char >= '0' && char <= '9' {
-		return true
+// excerpt renders the given line of the source with a caret underneath pointing at the pos
+// both line and pos are 1 based, if the line does not exist in the source an empty string is returned
+func excerpt(source string, line, pos int) string {
+	return excerptFrom(source, 1, line, pos)
+}
+
+// excerptFrom renders an excerpt from a partial source that starts at the given line number
+func excerptFrom(source string, first, line, pos int) string {
+	if line < first || pos < 1 {
+		return ""
+	}
+
+	lines := strings.Split(source, "\n")
+	if line-first >= len(lines) {
+		return ""
+	}
+
+	text := strings.TrimSuffix(lines[line-first], "\r")
+	gutter := strconv.Itoa(line) + " | "
+
+	// keep any tabs in the padding so the caret lines up with the source when rendered
+	var (
+		padding strings.Builder
+		i       int
+	)
+	for _, r := range text {
+		if i++; i >= pos {
+			break
+		}
+
+		if r == '\t' {
+			padding.WriteByte('\t')
+		} else {
+			padding.WriteByte(' ')
+		}
+	}
+
+	return gutter + text + "\n" +
+		strings.Repeat(" ", len(gutter)-2) + "| " + padding.String() + "^"
+}
+
+// isIdentChar checks if the provided char is a valid character for an identifier
+// any unicode letter can be used in an identifier
+func isIdentChar(char rune, subsequent ...bool) bool {
+	// numbers and hyphens are allowed in idents but not as the first character
+	next := len(subsequent) > 0 && subsequent[0]
+
+	if char >= utf8.RuneSelf {
+		return unicode.IsLetter(char) || next && unicode.IsDigit(char)
 	}
 
 	return char >= 'a' && char <= 'z' ||
 		char >= 'A' && char <= 'Z' ||
-		char == '_'
+		char == '_' ||
+		next && (isDigit(byte(char)) || char == '-')
+}
+
+// isHexDigit checks if the byte is a hexadecimal character
+func isHexDigit(char byte) bool {
+	return isDigit(char) || char >= 'a' && char <= 'f' || char >= 'A' && char <= 'F'
 }
 
 // isDigit checks if the byte is a numeric character
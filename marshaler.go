@@ -0,0 +1,65 @@
+package icl
+
+import (
+	"reflect"
+)
+
+// Unmarshaler is implemented by types that can decode themselves from an icl node
+//
+// Assignments pass their value node (eg a *MapNode or *SliceNode) and blocks pass the *BlockNode itself, when a block
+// is stored in a map the param used as the map key is removed before the node is passed on
+type Unmarshaler interface {
+	UnmarshalICL(Node) error
+}
+
+// Marshaler is implemented by types that can encode themselves into an icl node
+//
+// Fields may return either a value node or a *BlockNode, the name of the assignment or block will be set from the
+// struct tag
+type Marshaler interface {
+	MarshalICL() (Node, error)
+}
+
+var (
+	marshalerType   = reflect.TypeFor[Marshaler]()
+	unmarshalerType = reflect.TypeFor[Unmarshaler]()
+)
+
+// unmarshaler returns the Unmarshaler implemented by the value
+// nil pointers will be allocated so they can be unmarshaled into
+func unmarshaler(rv reflect.Value) (Unmarshaler, bool) {
+	rt := rv.Type()
+
+	if rt.Kind() == reflect.Pointer && rt.Implements(unmarshalerType) {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rt.Elem()))
+		}
+
+		return rv.Interface().(Unmarshaler), true
+	}
+
+	if rv.CanAddr() && reflect.PointerTo(rt).Implements(unmarshalerType) {
+		return rv.Addr().Interface().(Unmarshaler), true
+	}
+
+	return nil, false
+}
+
+// marshaler returns the Marshaler implemented by the value
+func marshaler(rv reflect.Value) (Marshaler, bool) {
+	rt := rv.Type()
+
+	if rt.Implements(marshalerType) {
+		if rt.Kind() == reflect.Pointer && rv.IsNil() {
+			return nil, false
+		}
+
+		return rv.Interface().(Marshaler), true
+	}
+
+	if rv.CanAddr() && reflect.PointerTo(rt).Implements(marshalerType) {
+		return rv.Addr().Interface().(Marshaler), true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,101 @@
+package icl
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// parseInt parses an integer literal
+//
+// hex (0x), octal (0o) and binary (0b) literals are supported along with _ separators, unlike go a leading 0 does
+// not make the number octal. exponent literals such as 1e6 are accepted as long as they are a whole number
+func parseInt(s string, bitSize int) (int64, error) {
+	i, err := strconv.ParseInt(intLiteral(s), 0, bitSize)
+	if err == nil || !isExponent(s, err) {
+		return i, err
+	}
+
+	f, ferr := strconv.ParseFloat(s, 64)
+	if ferr != nil || f != math.Trunc(f) {
+		return i, err
+	}
+
+	return strconv.ParseInt(strconv.FormatFloat(f, 'f', -1, 64), 10, bitSize)
+}
+
+// parseUint parses an unsigned integer literal, see parseInt for the supported formats
+func parseUint(s string, bitSize int) (uint64, error) {
+	i, err := strconv.ParseUint(intLiteral(s), 0, bitSize)
+	if err == nil || !isExponent(s, err) {
+		return i, err
+	}
+
+	f, ferr := strconv.ParseFloat(s, 64)
+	if ferr != nil || f < 0 || f != math.Trunc(f) {
+		return i, err
+	}
+
+	return strconv.ParseUint(strconv.FormatFloat(f, 'f', -1, 64), 10, bitSize)
+}
+
+// parseFloat parses a float literal, exponents, _ separators, inf and nan are all supported
+func parseFloat(s string, bitSize int) (float64, error) {
+	return strconv.ParseFloat(s, bitSize)
+}
+
+// formatFloat formats a float with the given precision, infinities and nan are written as the inf and nan keywords
+// so that they can be parsed back
+func formatFloat(f float64, precision int) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	case math.IsNaN(f):
+		return "nan"
+	}
+
+	return strconv.FormatFloat(f, 'f', precision, 64)
+}
+
+// intLiteral strips any leading zeros from a decimal literal so it is not parsed as octal
+func intLiteral(s string) string {
+	var sign string
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+	}
+
+	if len(s) < 2 || s[0] != '0' || !isDigit(s[1]) {
+		return sign + s
+	}
+
+	s = strings.TrimLeft(s, "0")
+	if s == "" || !isDigit(s[0]) {
+		s = "0" + s
+	}
+
+	return sign + s
+}
+
+// isExponent checks if a literal that failed to parse as an integer was written with an exponent
+func isExponent(s string, err error) bool {
+	if !errors.Is(err, strconv.ErrSyntax) {
+		return false
+	}
+
+	return !isHexLiteral(s) && strings.ContainsAny(s, "eE")
+}
+
+// isHexLiteral checks if the number literal has a hex prefix
+func isHexLiteral(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+
+	return strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X")
+}
+
+// isFloatKeyword checks if the identifier is one of the special float values
+func isFloatKeyword(s string) bool {
+	return s == "inf" || s == "nan"
+}
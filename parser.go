@@ -1,27 +1,93 @@
 package icl
 
-import "fmt"
+import (
+	"fmt"
+	"iter"
+	"strings"
+)
 
 type prefixParser func() Node
 
+// ParseOptions configures how tolerant the parser is of issues in a document
+type ParseOptions struct {
+	// Strict causes every token the parser would otherwise skip over to be reported as an error
+	Strict bool
+}
+
+// Diagnostic describes a single issue found by the parser
+type Diagnostic struct {
+	Message string
+	Line    int
+	Pos     int
+	Token   Token
+	// Excerpt is the offending line of the document with a caret pointing at the token
+	Excerpt string
+}
+
+// Error implements error
+func (d *Diagnostic) Error() string {
+	msg := fmt.Sprintf("%s -- [line(%d) pos(%d)]", d.Message, d.Line, d.Pos)
+	if d.Excerpt != "" {
+		msg += "\n" + d.Excerpt
+	}
+
+	return msg
+}
+
+// ParseError is returned when the parser found one or more issues with a document
+type ParseError struct {
+	Diagnostics []*Diagnostic
+}
+
+// Error implements error
+func (e *ParseError) Error() string {
+	msgs := make([]string, 0, len(e.Diagnostics))
+	for _, d := range e.Diagnostics {
+		msgs = append(msgs, d.Error())
+	}
+
+	return "icl: parse error\n" + strings.Join(msgs, "\n")
+}
+
+// Unwrap returns each of the diagnostics as an error
+func (e *ParseError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Diagnostics))
+	for _, d := range e.Diagnostics {
+		errs = append(errs, d)
+	}
+
+	return errs
+}
+
 type Parser struct {
-	lex *Lexer
+	lex  *Lexer
+	opts ParseOptions
 
-	errors    []error
+	errors    []*Diagnostic
 	curToken  Token
 	peekToken Token
+	// depth is the number of unclosed braces and brackets up to and including the current token
+	depth int
 
 	prefixParsers map[TokenType]prefixParser
+
+	// comments holds the comments that could not be attached to a node once Nodes has finished
+	comments []Token
 }
 
 // New creates a new parser for the provided lexer
-func NewParser(lex *Lexer) *Parser {
+// the parser will run in lenient mode unless ParseOptions are provided
+func NewParser(lex *Lexer, opts ...ParseOptions) *Parser {
 	p := &Parser{
 		lex:           lex,
 		prefixParsers: make(map[TokenType]prefixParser),
 	}
 
-	p.registerPrefixParser(TknIdent, p.parseIdentifier)
+	if len(opts) > 0 {
+		p.opts = opts[0]
+	}
+
+	p.registerPrefixParser(TknIdent, p.parseValueIdentifier)
 	p.registerPrefixParser(TknNumber, p.parseNumberNode)
 	p.registerPrefixParser(TknNull, p.parseNullNode)
 	p.registerPrefixParser(TknTrue, p.parseBooleanNode)
@@ -40,22 +106,77 @@ func NewParser(lex *Lexer) *Parser {
 // ParseProgram parses the tokens in the lexer into an AST
 func (p *Parser) Parse() *Ast {
 	program := &Ast{}
+	program.Nodes, program.Comments = p.parseNodeList(TknEof)
 
-	for p.curToken.Type != TknEof {
-		stmt := p.parseNode()
-		if stmt != nil {
-			program.Nodes = append(program.Nodes, stmt)
-		}
-
-		p.nextToken()
+	// excerpts can only be rendered from the Ast if the lexer was given the whole document
+	if !p.lex.streaming {
+		program.source = p.lex.input
 	}
 
 	return program
 }
 
+// Nodes returns an iterator over the top level nodes of the document
+//
+// each node is yielded as soon as it has been parsed so the document never needs to be held in memory in full,
+// any diagnostics found along the way are yielded as a *Diagnostic error before the node they belong to and
+// a failure to read the input is yielded as the final error
+//
+// dotted keys are merged into earlier blocks with the same name, so a block without params can still have nodes
+// added to its body after it has been yielded
+func (p *Parser) Nodes() iter.Seq2[Node, error] {
+	return func(yield func(Node, error) bool) {
+		reported := len(p.errors)
+		diagnostics := func() bool {
+			for ; reported < len(p.errors); reported++ {
+				if !yield(nil, p.errors[reported]) {
+					return false
+				}
+			}
+
+			return true
+		}
+
+		var stopped bool
+		p.comments = p.parseNodes(TknEof, func(node Node) bool {
+			stopped = !diagnostics() || !yield(node, nil)
+			return !stopped
+		})
+
+		if stopped || !diagnostics() {
+			return
+		}
+
+		if err := p.lex.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
 // Errors returns a slice of errors generated by the parser
 func (p *Parser) Errors() []error {
-	return p.errors
+	errs := make([]error, 0, len(p.errors))
+	for _, d := range p.errors {
+		errs = append(errs, d)
+	}
+
+	return errs
+}
+
+// Err returns a *ParseError containing all the diagnostics generated by the parser
+// if no issues were found then nil will be returned
+//
+// if the lexer failed to read its input then the read error will be returned instead
+func (p *Parser) Err() error {
+	if err := p.lex.Err(); err != nil {
+		return err
+	}
+
+	if len(p.errors) == 0 {
+		return nil
+	}
+
+	return &ParseError{Diagnostics: p.errors}
 }
 
 // registerPrefixParser registers a prefix parser funcion for the token type
@@ -63,15 +184,37 @@ func (p *Parser) registerPrefixParser(tknType TokenType, parser prefixParser) {
 	p.prefixParsers[tknType] = parser
 }
 
-func (p *Parser) errorf(format string, args ...any) {
-	lineKey := fmt.Sprintf(" -- [line(%d) pos(%d)]", p.peekToken.Line, p.peekToken.Pos)
-	p.errors = append(p.errors, fmt.Errorf(format+lineKey, args...))
+// errorf records a diagnostic against the given token
+func (p *Parser) errorf(tkn Token, format string, args ...any) error {
+	d := &Diagnostic{
+		Message: fmt.Sprintf(format, args...),
+		Line:    tkn.Line,
+		Pos:     tkn.Pos,
+		Token:   tkn,
+		Excerpt: p.lex.excerpt(tkn.Line, tkn.Pos),
+	}
+	p.errors = append(p.errors, d)
+
+	return d
 }
 
 // nextToken advances the lexer to the next token
 func (p *Parser) nextToken() {
 	p.curToken = p.peekToken
 	p.peekToken = p.lex.NextToken()
+	p.depth += nesting(p.curToken.Type)
+}
+
+// nesting returns the change in depth caused by the token
+func nesting(tknType TokenType) int {
+	switch tknType {
+	case TknLBrace, TknLBracket:
+		return 1
+	case TknRBrace, TknRBracket:
+		return -1
+	}
+
+	return 0
 }
 
 // parseNode parses the next statement in the lexers token stream
@@ -79,16 +222,50 @@ func (p *Parser) parseNode() Node {
 	switch p.curToken.Type {
 	case TknIdent:
 		if p.peekTokenIs(TknLBrace) || p.peekTokenIs(TknIdent) || p.peekTokenIs(TknString) {
-			return p.parseBlockNode()
+			return expandDottedKey(p.parseBlockNode())
 		}
-		return p.parseAssignNode()
+		return expandDottedKey(p.parseAssignNode())
 	case TknComment:
 		return nil
+	case TknIllegal:
+		if p.unterminated() {
+			return nil
+		}
+
+		if p.opts.Strict {
+			p.errorf(p.curToken, "illegal token %q", p.curToken.Literal)
+			return nil
+		}
+		// illegal tokens are always reported, in lenient mode they fail to parse as an expression
+		return p.parseExpression()
 	default:
+		if p.opts.Strict {
+			p.errorf(p.curToken, "unexpected %s, expected an assignment or block", p.curToken.Type)
+			return nil
+		}
 		return p.parseExpression()
 	}
 }
 
+// unterminated reports the current token if it is the start of a block comment or heredoc that was never closed
+// they swallow the rest of the document so this is reported in both strict and lenient mode
+func (p *Parser) unterminated() bool {
+	if !p.curTokenIs(TknIllegal) {
+		return false
+	}
+
+	switch lit := p.curToken.Literal; {
+	case lit == "/*":
+		p.errorf(p.curToken, "unterminated block comment")
+	case strings.HasPrefix(lit, "<<") && len(lit) > 2:
+		p.errorf(p.curToken, "unterminated heredoc, closing marker %q not found", strings.TrimLeft(lit, "<-"))
+	default:
+		return false
+	}
+
+	return true
+}
+
 // peekTokenIs checks if the next token in the stream is of the provided type
 func (p *Parser) peekTokenIs(tknType TokenType) bool {
 	return p.peekToken.Type == tknType
@@ -101,12 +278,59 @@ func (p *Parser) curTokenIs(tknType TokenType) bool {
 
 // peekError checks that the next token is of the given type
 // if not then an error will be generated
-func (p *Parser) peekError(tknType TokenType) (err error) {
+func (p *Parser) peekError(tknType TokenType) error {
 	if !p.peekTokenIs(tknType) {
-		p.errorf("Unexpected token type: expected(%s) found(%s)", tknType, p.peekToken.Type)
+		return p.errorf(p.peekToken, "Unexpected token type: expected(%s) found(%s)", tknType, p.peekToken.Type)
 	}
 
-	return
+	return nil
+}
+
+// synchronize skips the remaining tokens on the current line
+// this stops a single malformed statement from generating a cascade of diagnostics
+//
+// skipping stops before the close token of the enclosing list, depth is the depth the list was opened at
+func (p *Parser) synchronize(closeToken TokenType, depth int) {
+	line := p.curToken.Line
+	for !p.peekTokenIs(TknEof) && p.peekToken.Line == line {
+		if p.peekTokenIs(closeToken) && p.depth <= depth {
+			return
+		}
+
+		p.nextToken()
+	}
+}
+
+// collectComments advances the token stream past any comments starting at the current token
+func (p *Parser) collectComments() (comments []Token) {
+	for p.curTokenIs(TknComment) {
+		comments = append(comments, p.curToken)
+		p.nextToken()
+	}
+
+	return comments
+}
+
+// collectPeekComments advances the token stream until the next token is no longer a comment
+func (p *Parser) collectPeekComments() (comments []Token) {
+	for p.peekTokenIs(TknComment) {
+		p.nextToken()
+		comments = append(comments, p.curToken)
+	}
+
+	return comments
+}
+
+// inlineComment advances past the next token if it is a comment on the same line as the current token
+func (p *Parser) inlineComment() *Token {
+	if !p.peekTokenIs(TknComment) || p.peekToken.Line != p.curToken.Line {
+		return nil
+	}
+
+	p.nextToken()
+	tkn := p.curToken
+
+	return &tkn
 }
 
 // expectPeek runs the peekError method and advances the token streabm if no erro is found
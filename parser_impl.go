@@ -2,93 +2,254 @@ package icl
 
 import (
 	"slices"
+	"strings"
+	"unicode/utf8"
 )
 
 func (p *Parser) parseExpression(allowed ...TokenType) Node {
 	if len(allowed) > 0 && !slices.Contains(allowed, p.curToken.Type) {
-		p.errorf("token type %s is not allowed here", p.curToken.Type)
+		p.errorf(p.curToken, "token type %s is not allowed here", p.curToken.Type)
+		return nil
+	}
+
+	if p.unterminated() {
 		return nil
 	}
 
 	prefix := p.prefixParsers[p.curToken.Type]
 	if prefix == nil {
-		p.errorf("no prefix parser found for %s", p.curToken.Type)
+		p.errorf(p.curToken, "no prefix parser found for %s", p.curToken.Type)
 		return nil
 	}
 
 	return prefix()
 }
 
-func (p *Parser) parseListEntries(closeToken TokenType) (list []Node) {
-	p.nextToken()
+// parseNodeList parses statements until either the close token or EOF is found
+// comments are attached to the statement they belong to, any that cannot be attached are returned
+func (p *Parser) parseNodeList(closeToken TokenType) (nodes []Node, comments []Token) {
+	comments = p.parseNodes(closeToken, func(node Node) bool {
+		nodes = append(nodes, node)
+		return true
+	})
+
+	return nodes, comments
+}
+
+// parseNodes parses nodes until the close token is found passing each one to yield as soon as it has been parsed
+// parsing stops early if yield returns false
+//
+// comments after the final node are attached to it as trailing comments, if that is not possible they are returned
+//
+// blocks from dotted keys are merged into the last block with the same name and no params in the list, that block
+// may have already been yielded so callers must not consume blocks without params until parsing has finished
+func (p *Parser) parseNodes(closeToken TokenType, yield func(Node) bool) (comments []Token) {
+	var (
+		last Node
+		// blocks without params by name, these are the blocks dotted keys can be merged into
+		scope = make(map[string]*BlockNode)
+		// depth of the list itself so the close token can be told apart from the close of a nested map or slice
+		depth = p.depth - nesting(p.curToken.Type)
+	)
+
+	for !p.curTokenIs(closeToken) && !p.curTokenIs(TknEof) {
+		if p.curTokenIs(TknComment) {
+			comments = append(comments, p.curToken)
+			p.nextToken()
+			continue
+		}
 
-	if p.curTokenIs(closeToken) {
-		return list
+		errCount := len(p.errors)
+
+		stmt := p.parseNode()
+		if c, ok := dottedLeaf(stmt).(commented); ok {
+			c.comments().Leading = comments
+			c.comments().Inline = p.inlineComment()
+			comments = nil
+		}
+
+		// a malformed statement may have already consumed the close token of the list
+		closed := p.curTokenIs(closeToken) && p.depth < depth
+		if len(p.errors) > errCount && !closed {
+			p.synchronize(closeToken, depth)
+		}
+
+		// p.parseNode() leaves the cursor on the final token of the statement so we need to advance
+		// the cursor before the next parse
+		if !closed {
+			p.nextToken()
+		}
+
+		if stmt == nil {
+			continue
+		}
+
+		if block, ok := stmt.(*BlockNode); ok && len(block.Parameters) == 0 {
+			if target := scope[block.Token.Literal]; target != nil && (target.dotted || block.dotted) {
+				mergeBlock(target, block)
+
+				// trailing comments cannot be attached to a node that has been merged into an earlier one
+				last = nil
+				continue
+			}
+
+			scope[block.Token.Literal] = block
+		}
+
+		last = stmt
+		if !yield(stmt) {
+			return nil
+		}
 	}
 
-	// i dont like this procedure but i cant think of a better way atm
-	list = append(list, p.parseNode())
-	for p.peekTokenIs(TknComma) {
-		p.nextToken()
-		p.nextToken()
-		list = append(list, p.parseNode())
+	if last == nil || len(comments) == 0 {
+		return comments
 	}
 
-	if !p.expectPeek(closeToken) {
-		return nil
+	if c, ok := last.(commented); ok {
+		c.comments().Trailing = comments
+		comments = nil
 	}
 
-	return list
+	return comments
 }
 
-func (p *Parser) parseMapBody() map[Node]Node {
-	body := make(map[Node]Node)
-	closeToken := TknRBrace
+// parseListEntries parses the elements of a slice along with any comments attached to them
+func (p *Parser) parseListEntries(node *SliceNode) {
+	closeToken := TknRBracket
 
-	if p.curTokenIs(TknLBrace) {
-		p.nextToken()
-	}
+	// advance past [
+	p.nextToken()
+	leading := p.collectComments()
 
-	if p.curTokenIs(closeToken) {
-		return body
-	}
+	var comments []*Comments
+	for !p.curTokenIs(closeToken) {
+		entry := p.parseExpression()
+		if entry == nil {
+			return
+		}
 
-	if p.peekToken.Type != TknColon {
-		p.errorf("no prefix parser found for %s", p.curToken.Type)
-		return nil
+		node.Elements = append(node.Elements, entry)
+
+		entryComments := &Comments{Leading: leading}
+		var hasComma bool
+		leading, hasComma = p.parseEntryEnd(entryComments)
+		comments = append(comments, entryComments)
+
+		if !hasComma {
+			if !p.expectPeek(closeToken) {
+				return
+			}
+			break
+		}
+
+		p.nextToken()
+		leading = append(leading, p.collectComments()...)
 	}
 
-	for !p.peekTokenIs(closeToken) {
-		for p.curTokenIs(TknComment) {
-			p.nextToken()
+	if len(leading) > 0 {
+		if len(comments) > 0 {
+			comments[len(comments)-1].Trailing = leading
+		} else {
+			node.Comments = leading
 		}
+	}
 
-		if p.curTokenIs(closeToken) {
+	for _, c := range comments {
+		if len(c.Leading) > 0 || c.Inline != nil || len(c.Trailing) > 0 {
+			node.ElementComments = comments
 			break
 		}
+	}
+}
+
+// parseMapBody parses the entries of a map along with any comments attached to them
+func (p *Parser) parseMapBody(node *MapNode) {
+	closeToken := TknRBrace
+	node.Elements = make(map[Node]Node)
 
+	// advance past {
+	p.nextToken()
+	leading := p.collectComments()
+
+	var lastKey Node
+	for !p.curTokenIs(closeToken) {
 		key := p.parseExpression(TknIdent, TknString)
-		if !p.expectPeek(TknColon) {
-			return body
+		if key == nil || !p.expectPeek(TknColon) {
+			return
 		}
 
 		p.nextToken()
 		value := p.parseExpression()
+		if value == nil {
+			return
+		}
+
+		node.Elements[key] = value
+		lastKey = key
 
-		body[key] = value
+		entryComments := &Comments{Leading: leading}
+		var hasComma bool
+		leading, hasComma = p.parseEntryEnd(entryComments)
+		p.attachEntryComments(node, key, entryComments)
 
-		if p.peekToken.Type != closeToken {
-			if !p.expectPeek(TknComma) {
-				p.errorf("expected : or }")
+		if !hasComma {
+			if !p.expectPeek(closeToken) {
+				return
 			}
-			p.nextToken()
+			break
 		}
+
+		p.nextToken()
+		leading = append(leading, p.collectComments()...)
+	}
+
+	if len(leading) == 0 {
+		return
+	}
+
+	if lastKey != nil {
+		p.attachEntryComments(node, lastKey, &Comments{Trailing: leading})
+	} else {
+		node.Comments = leading
+	}
+}
+
+// parseEntryEnd consumes any comments following a map or slice entry along with the , separator if it
+// exists, any inline comment is attached to the entry and all other comments are returned
+func (p *Parser) parseEntryEnd(entry *Comments) (comments []Token, hasComma bool) {
+	entry.Inline = p.inlineComment()
+	comments = p.collectPeekComments()
+
+	if !p.peekTokenIs(TknComma) {
+		return comments, false
 	}
 
-	// advance past closing }
 	p.nextToken()
+	if entry.Inline == nil && len(comments) == 0 {
+		entry.Inline = p.inlineComment()
+	}
 
-	return body
+	return comments, true
+}
+
+// attachEntryComments merges the given comments into the comments stored against the map key
+func (p *Parser) attachEntryComments(node *MapNode, key Node, comments *Comments) {
+	if len(comments.Leading) == 0 && comments.Inline == nil && len(comments.Trailing) == 0 {
+		return
+	}
+
+	if node.EntryComments == nil {
+		node.EntryComments = make(map[Node]*Comments)
+	}
+
+	existing, ok := node.EntryComments[key]
+	if !ok {
+		node.EntryComments[key] = comments
+		return
+	}
+
+	existing.Trailing = append(existing.Trailing, comments.Trailing...)
 }
 
 // parseNullNode parses a token as a null literal expression
@@ -114,6 +275,19 @@ func (p *Parser) parseNumberNode() Node {
 	}
 }
 
+// parseValueIdentifier parses an identifier found in the position of a value
+// the inf and nan keywords are treated as numbers unless they are being used as a map key
+func (p *Parser) parseValueIdentifier() Node {
+	if isFloatKeyword(p.curToken.Literal) && !p.peekTokenIs(TknColon) {
+		tkn := p.curToken
+		tkn.Type = TknNumber
+
+		return &NumberNode{Token: tkn, Value: tkn.Literal}
+	}
+
+	return p.parseIdentifier()
+}
+
 // parseIdentifier parses an identifier token into an expression
 func (p *Parser) parseIdentifier() Node {
 	if p.curToken.Literal == "env" && p.peekTokenIs(TknLParen) {
@@ -128,7 +302,17 @@ func (p *Parser) parseIdentifier() Node {
 			return nil
 		}
 
-		n.Identifier = p.parseIdentifier().(*Identifier)
+		node := p.parseIdentifier()
+		ident, ok := node.(*Identifier)
+		if !ok {
+			// a nil node has already reported why it could not be parsed
+			if node != nil {
+				p.errorf(n.Token, "env() macro expects an identifier")
+			}
+			return nil
+		}
+
+		n.Identifier = ident
 
 		if !p.expectPeek(TknRParen) {
 			return nil
@@ -144,24 +328,27 @@ func (p *Parser) parseIdentifier() Node {
 }
 
 func (p *Parser) parseSliceNode() Node {
-	return &SliceNode{
-		Token:    p.curToken,
-		Elements: p.parseListEntries(TknRBracket),
-	}
+	n := &SliceNode{Token: p.curToken}
+	p.parseListEntries(n)
+
+	return n
 }
 
 func (p *Parser) parseMapNode() Node {
-	return &MapNode{
-		Token:    p.curToken,
-		Elements: p.parseMapBody(),
-	}
+	n := &MapNode{Token: p.curToken}
+	p.parseMapBody(n)
+
+	return n
 }
 
 // parseAssignNode parses a let statement from the lexers token stream
-func (p *Parser) parseAssignNode() *AssignNode {
+func (p *Parser) parseAssignNode() Node {
 	stmt := &AssignNode{Token: p.curToken}
 
 	if p.peekToken.Type != TknAssign {
+		if p.opts.Strict {
+			p.errorf(p.peekToken, "Unexpected token type: expected(%s) found(%s)", TknAssign, p.peekToken.Type)
+		}
 		return nil
 	}
 
@@ -173,6 +360,9 @@ func (p *Parser) parseAssignNode() *AssignNode {
 	p.nextToken()
 	p.nextToken()
 	stmt.Value = p.parseExpression()
+	if stmt.Value == nil {
+		return nil
+	}
 
 	return stmt
 }
@@ -190,7 +380,8 @@ func (p *Parser) parseBlockNode() Node {
 		p.nextToken()
 	}
 
-	if !p.expectPeek(TknLParen) {
+	if !p.curTokenIs(TknLBrace) {
+		p.errorf(p.curToken, "Unexpected token type: expected(%s) found(%s)", TknLBrace, p.curToken.Type)
 		return nil
 	}
 
@@ -202,17 +393,120 @@ func (p *Parser) parseBlockNode() Node {
 func (p *Parser) parseBlockBodyNode() *BlockBodyNode {
 	block := &BlockBodyNode{Token: p.curToken}
 
-	// loop until either a } or EOF token is found
-	for !p.curTokenIs(TknRBrace) && !p.curTokenIs(TknEof) {
-		stmt := p.parseNode()
-		if stmt != nil {
-			block.Nodes = append(block.Nodes, stmt)
-		}
+	// advance past {
+	p.nextToken()
 
-		// p.parseNode() leaves the cursor on the final token of the statement so we need to advance
-		// the cursor before the next parse
-		p.nextToken()
+	block.Nodes, block.Comments = p.parseNodeList(TknRBrace)
+
+	if p.curTokenIs(TknEof) && p.opts.Strict {
+		p.errorf(block.Token, "unterminated block")
 	}
 
 	return block
 }
+
+// expandDottedKey expands an assignment or block with a dotted key into nested blocks
+// eg server.tls.enabled = true becomes server { tls { enabled = true } }
+func expandDottedKey(node Node) Node {
+	var tkn *Token
+
+	switch n := node.(type) {
+	case *AssignNode:
+		tkn = &n.Token
+	case *BlockNode:
+		tkn = &n.Token
+	default:
+		return node
+	}
+
+	if !strings.Contains(tkn.Literal, ".") {
+		return node
+	}
+
+	keys := strings.Split(tkn.Literal, ".")
+	tokens := make([]Token, len(keys))
+	pos := tkn.Pos
+	for i, key := range keys {
+		tokens[i] = Token{Type: TknIdent, Literal: key, Line: tkn.Line, Pos: pos}
+		pos += utf8.RuneCountInString(key) + 1
+	}
+
+	// the original node keeps the final key
+	*tkn = tokens[len(tokens)-1]
+	if a, ok := node.(*AssignNode); ok {
+		a.Name = &Identifier{Token: *tkn, Value: tkn.Literal}
+	}
+
+	for i := len(tokens) - 2; i >= 0; i-- {
+		node = &BlockNode{
+			Token:  tokens[i],
+			Body:   &BlockBodyNode{Token: tokens[i], Nodes: []Node{node}},
+			dotted: true,
+		}
+	}
+
+	return node
+}
+
+// mergeBlock merges the body of a block into an earlier block with the same name
+// nested blocks are merged the same way so server.tls.cert can be merged into server { tls { ... } }
+func mergeBlock(into, from *BlockNode) {
+	if comments := from.Comments.all(); len(comments) > 0 {
+		if c, ok := firstCommented(from.Body.Nodes); ok {
+			c.comments().Leading = slices.Concat(comments, c.comments().Leading)
+		} else {
+			into.Body.Comments = append(into.Body.Comments, comments...)
+		}
+	}
+
+	for _, node := range from.Body.Nodes {
+		block, ok := node.(*BlockNode)
+		if !ok || len(block.Parameters) > 0 {
+			into.Body.Nodes = append(into.Body.Nodes, node)
+			continue
+		}
+
+		if target := findMergeTarget(into.Body.Nodes, block.Token.Literal); target != nil && (target.dotted || block.dotted) {
+			mergeBlock(target, block)
+			continue
+		}
+
+		into.Body.Nodes = append(into.Body.Nodes, node)
+	}
+
+	into.Body.Comments = append(into.Body.Comments, from.Body.Comments...)
+}
+
+// findMergeTarget finds the last block in the list with the given name and no params
+func findMergeTarget(nodes []Node, name string) *BlockNode {
+	for i := len(nodes) - 1; i >= 0; i-- {
+		if block, ok := nodes[i].(*BlockNode); ok && len(block.Parameters) == 0 && block.Token.Literal == name {
+			return block
+		}
+	}
+
+	return nil
+}
+
+// firstCommented returns the first node in the list that can have comments attached
+func firstCommented(nodes []Node) (commented, bool) {
+	for _, node := range nodes {
+		if c, ok := node.(commented); ok {
+			return c, true
+		}
+	}
+
+	return nil, false
+}
+
+// dottedLeaf finds the node that was written in the document for a block expanded from a dotted key
+func dottedLeaf(node Node) Node {
+	for {
+		block, ok := node.(*BlockNode)
+		if !ok || !block.dotted {
+			return node
+		}
+
+		node = block.Body.Nodes[len(block.Body.Nodes)-1]
+	}
+}
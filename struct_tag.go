@@ -7,10 +7,19 @@ import (
 )
 
 type tags struct {
-	key       string
-	env       string
-	precision int
-	isParam   bool
+	key        string
+	env        string
+	precision  int
+	isParam    bool
+	isComments bool
+	required   bool
+	hasDefault bool
+	// def contains the raw default value for the field
+	def string
+	// layout is the time layout used to parse and format time.Time fields
+	layout string
+	// bytes decodes integer fields from byte size strings such as 512MiB and encodes them back the same way
+	bytes bool
 }
 
 func parseTags(s string) (*tags, error) {
@@ -18,12 +27,23 @@ func parseTags(s string) (*tags, error) {
 		return &tags{isParam: true}, nil
 	}
 
-	parts := strings.Split(s, ",")
-	t := tags{
-		key:       parts[0],
-		precision: -1,
+	if s == ".comments" {
+		return &tags{isComments: true}, nil
 	}
 
+	var t tags
+
+	// default values can contain commas so the default option must always be the last option in the tag
+	if i := strings.Index(s, ",default="); i >= 0 {
+		t.hasDefault = true
+		t.def = s[i+len(",default="):]
+		s = s[:i]
+	}
+
+	parts := splitOptions(s)
+	t.key = parts[0]
+	t.precision = -1
+
 	if strings.Contains(t.key, ".") {
 		parts := strings.Split(t.key, ".")
 		if len(parts) != 2 {
@@ -39,12 +59,45 @@ func parseTags(s string) (*tags, error) {
 		t.precision = precision
 	}
 
-	for _, part := range parts {
-		if !strings.HasPrefix(part, "env(") && part[len(part)-1] != ')' {
-			continue
+	for _, part := range parts[1:] {
+		switch {
+		case part == "required":
+			t.required = true
+		case part == "bytes":
+			t.bytes = true
+		case strings.HasPrefix(part, "env(") && strings.HasSuffix(part, ")"):
+			t.env = part[4 : len(part)-1]
+		case strings.HasPrefix(part, "layout(") && strings.HasSuffix(part, ")"):
+			t.layout = part[7 : len(part)-1]
 		}
-		t.env = part[4 : len(part)-1]
 	}
 
 	return &t, nil
 }
+
+// splitOptions splits the tag on commas that are not wrapped in parentheses
+func splitOptions(s string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(parts, s[start:])
+}
@@ -0,0 +1,151 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/indeedhat/icl"
+	"github.com/stretchr/testify/require"
+)
+
+type anyTarget struct {
+	Any     any            `icl:"any"`
+	Map     map[string]any `icl:"map"`
+	Slice   []any          `icl:"slice"`
+	Options map[string]any `icl:"options"`
+	Plugins []any          `icl:"plugin"`
+}
+
+var anyUnmarshalTests = map[string]unmarshalTest{
+	"any string": {
+		`any = "str"`,
+		anyTarget{Any: "str"},
+		"",
+	},
+	"any bool": {
+		`any = true`,
+		anyTarget{Any: true},
+		"",
+	},
+	"any int": {
+		`any = -12`,
+		anyTarget{Any: int64(-12)},
+		"",
+	},
+	"any float": {
+		`any = 1.5`,
+		anyTarget{Any: 1.5},
+		"",
+	},
+	"any null": {
+		`any = null`,
+		anyTarget{},
+		"",
+	},
+	"any slice": {
+		`any = [1, "two", [3.5]]`,
+		anyTarget{Any: []any{int64(1), "two", []any{3.5}}},
+		"",
+	},
+	"any map": {
+		`any = {a: 1, b: {c: null}}`,
+		anyTarget{Any: map[string]any{"a": int64(1), "b": map[string]any{"c": nil}}},
+		"",
+	},
+	"any invalid node": {
+		`any = [ident]`,
+		anyTarget{},
+		".any: invalid node type IDENT\nline(1) pos(8)\n1 | any = [ident]\n  |        ^",
+	},
+	"map of any": {
+		`map = {one: 1, two: "2", three: [true]}`,
+		anyTarget{Map: map[string]any{"one": int64(1), "two": "2", "three": []any{true}}},
+		"",
+	},
+	"slice of any": {
+		`slice = [{a: 1}, null, 2.25]`,
+		anyTarget{Slice: []any{map[string]any{"a": int64(1)}, nil, 2.25}},
+		"",
+	},
+	"block into map of any": {
+		`options "first" "second" {
+			enabled = true
+			limits {
+				max = 10
+			}
+			rule "a" {}
+			rule "b" {}
+		}`,
+		anyTarget{Options: map[string]any{
+			icl.ParamsKey: []any{"first", "second"},
+			"enabled":     true,
+			"limits":      map[string]any{"max": int64(10)},
+			"rule": []any{
+				map[string]any{icl.ParamsKey: []any{"a"}},
+				map[string]any{icl.ParamsKey: []any{"b"}},
+			},
+		}},
+		"",
+	},
+	"block into any": {
+		`any {
+			key = "value"
+		}`,
+		anyTarget{Any: map[string]any{"key": "value"}},
+		"",
+	},
+	"blocks into slice of any": {
+		`plugin "one" {
+			enabled = true
+		}
+		plugin "two" {}`,
+		anyTarget{Plugins: []any{
+			map[string]any{icl.ParamsKey: []any{"one"}, "enabled": true},
+			map[string]any{icl.ParamsKey: []any{"two"}},
+		}},
+		"",
+	},
+	"block into map of any duplicate": {
+		`options {}
+		options {}`,
+		anyTarget{Options: map[string]any{}},
+		".options: multiple \"options\" blocks found for field that is not a slice\nline(2) pos(3)\n2 | \t\toptions {}\n  | \t\t^",
+	},
+}
+
+func TestUnmarshalAny(t *testing.T) {
+	for key, test := range anyUnmarshalTests {
+		t.Run(key, func(t *testing.T) {
+			tgt := anyTarget{}
+			err := icl.UnMarshalString(test.document, &tgt)
+
+			if test.error != "" {
+				require.NotNil(t, err)
+				require.Equal(t, test.error, err.Error())
+			} else {
+				require.Nil(t, err)
+			}
+
+			require.Equal(t, test.output, tgt)
+		})
+	}
+}
+
+type anyMarshalTarget struct {
+	Any     any            `icl:"any"`
+	Options map[string]any `icl:"options"`
+}
+
+func TestMarshalAny(t *testing.T) {
+	target := anyMarshalTarget{
+		Any:     []any{int64(1), "two", nil},
+		Options: map[string]any{"enabled": true, "ratio": 0.5},
+	}
+
+	document, err := icl.MarshalString(target)
+	require.Nil(t, err)
+	require.Equal(t, "any = [1, \"two\", null]\noptions = {\n    \"enabled\": true,\n    \"ratio\": 0.5,\n}\n", document)
+
+	var decoded anyMarshalTarget
+	require.Nil(t, icl.UnMarshalString(document, &decoded))
+	require.Equal(t, target, decoded)
+}
@@ -0,0 +1,157 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/indeedhat/icl"
+)
+
+type benchUpstream struct {
+	Name    string            `icl:".param"`
+	Hosts   []string          `icl:"hosts"`
+	Weight  float64           `icl:"weight.2"`
+	Healthy bool              `icl:"healthy"`
+	Headers map[string]string `icl:"headers"`
+}
+
+type benchRoute struct {
+	Path     string          `icl:".param"`
+	Method   string          `icl:".param"`
+	Comments []string        `icl:".comments"`
+	Timeout  int             `icl:"timeout"`
+	Retries  uint8           `icl:"retries"`
+	Upstream []benchUpstream `icl:"upstream"`
+}
+
+type benchConfig struct {
+	Version int              `icl:"version"`
+	Name    string           `icl:"name"`
+	Debug   bool             `icl:"debug"`
+	Tags    []string         `icl:"tags"`
+	Limits  map[string]int   `icl:"limits"`
+	Routes  []benchRoute     `icl:"route"`
+	Extra   map[string]any   `icl:"extra"`
+	Envs    []map[string]int `icl:"envs"`
+}
+
+// benchDocument generates a config document with the given number of routes, each route is roughly 20 lines long
+func benchDocument(routes int) string {
+	var b strings.Builder
+
+	b.WriteString("version = 1\n")
+	b.WriteString("name = \"benchmark \\\"service\\\"\"\n")
+	b.WriteString("debug = false\n")
+	b.WriteString("tags = [\"alpha\", \"beta\", \"gamma\", 'delta']\n")
+	b.WriteString("limits = {\n    connections: 1024,\n    requests: 50000,\n}\n")
+	b.WriteString("extra = {\n    region: \"eu-west-1\",\n    replicas: 3,\n    ratio: 0.75,\n}\n")
+	b.WriteString("envs = [{dev: 1, prod: 2}, {stage: 3}]\n\n")
+
+	for i := 0; i < routes; i++ {
+		fmt.Fprintf(&b, "# route %d handles the /api/v1/resource-%d endpoint\n", i, i)
+		fmt.Fprintf(&b, "# it is generated for the benchmarks\n")
+		fmt.Fprintf(&b, "route \"/api/v1/resource/%d\" \"GET\" {\n", i)
+		fmt.Fprintf(&b, "    timeout = %d\n", 30+i%60)
+		fmt.Fprintf(&b, "    retries = %d # inline comment\n", i%5)
+		for j := 0; j < 2; j++ {
+			fmt.Fprintf(&b, "    upstream \"backend-%d-%d\" {\n", i, j)
+			fmt.Fprintf(&b, "        hosts = [\"10.0.%d.%d:8080\", \"10.0.%d.%d:8081\"]\n", i%255, j, i%255, j+1)
+			fmt.Fprintf(&b, "        weight = %d.%02d\n", j, i%100)
+			fmt.Fprintf(&b, "        healthy = %t\n", i%2 == 0)
+			b.WriteString("        headers = {\n")
+			fmt.Fprintf(&b, "            x_request_id: \"req-%d-%d\",\n", i, j)
+			b.WriteString("            x_forwarded_proto: \"https\",\n")
+			b.WriteString("        }\n")
+			b.WriteString("    }\n")
+		}
+		b.WriteString("}\n\n")
+	}
+
+	return b.String()
+}
+
+// the documents are only built when a benchmark needs them so that running the tests does not pay for them
+var (
+	benchSmall = sync.OnceValue(func() string { return benchDocument(100) })
+	benchLarge = sync.OnceValue(func() string { return benchDocument(5000) })
+)
+
+func benchmarkSizes(b *testing.B, fn func(b *testing.B, document string)) {
+	for name, build := range map[string]func() string{"small": benchSmall, "large": benchLarge} {
+		b.Run(name, func(b *testing.B) {
+			document := build()
+			b.SetBytes(int64(len(document)))
+			b.ReportAllocs()
+			fn(b, document)
+		})
+	}
+}
+
+func BenchmarkLexer(b *testing.B) {
+	benchmarkSizes(b, func(b *testing.B, document string) {
+		for i := 0; i < b.N; i++ {
+			l := icl.NewLexer(document)
+			for l.NextToken().Type != icl.TknEof {
+			}
+		}
+	})
+}
+
+func BenchmarkParse(b *testing.B) {
+	benchmarkSizes(b, func(b *testing.B, document string) {
+		data := []byte(document)
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			if _, err := icl.Parse(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	benchmarkSizes(b, func(b *testing.B, document string) {
+		a, err := icl.ParseString(document)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			var tgt benchConfig
+			if err := a.Unmarshal(&tgt); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	benchmarkSizes(b, func(b *testing.B, document string) {
+		var tgt benchConfig
+		if err := icl.UnMarshalString(document, &tgt); err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			if _, err := icl.Marshal(tgt); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkUnmarshalReader(b *testing.B) {
+	benchmarkSizes(b, func(b *testing.B, document string) {
+		for i := 0; i < b.N; i++ {
+			var tgt benchConfig
+			if err := icl.UnMarshalReader(strings.NewReader(document), &tgt); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
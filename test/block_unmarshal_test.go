@@ -0,0 +1,142 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/indeedhat/icl"
+	"github.com/stretchr/testify/require"
+)
+
+type blockTarget struct {
+	Block  blockInner             `icl:"block"`
+	Blocks []blockInner           `icl:"blocks"`
+	Keyed  map[string]blockInner  `icl:"keyed"`
+	KeyPtr map[string]*blockInner `icl:"key_ptr"`
+}
+
+type blockInner struct {
+	P1   string `icl:".param"`
+	Data bool   `icl:"data"`
+}
+
+var blockUnmarshalTests = map[string]unmarshalTest{
+	"block valid": {
+		`block "param" {
+			data = true
+		}`,
+		blockTarget{Block: blockInner{P1: "param", Data: true}},
+		"",
+	},
+	"block slice valid": {
+		`blocks "one" {
+			data = true
+		}
+		blocks "two" {
+			data = false
+		}`,
+		blockTarget{Blocks: []blockInner{{P1: "one", Data: true}, {P1: "two"}}},
+		"",
+	},
+	"block map valid": {
+		`keyed "one" {
+			data = true
+		}
+		keyed "two" "param" {
+			data = false
+		}`,
+		blockTarget{Keyed: map[string]blockInner{
+			"one": {Data: true},
+			"two": {P1: "param"},
+		}},
+		"",
+	},
+	"block map pointer valid": {
+		`key_ptr "one" {
+			data = true
+		}`,
+		blockTarget{KeyPtr: map[string]*blockInner{"one": {Data: true}}},
+		"",
+	},
+	"block map missing key": {
+		`keyed {
+			data = true
+		}`,
+		blockTarget{},
+		".keyed: \"keyed\" blocks must have a param to use as the map key\nline(1) pos(1)\n1 | keyed {\n  | ^",
+	},
+	"block map duplicate key": {
+		`keyed "one" {}
+		keyed "one" {}`,
+		blockTarget{Keyed: map[string]blockInner{"one": {}}},
+		".keyed[\"one\"]: multiple \"keyed\" blocks found with the same key\nline(2) pos(3)\n2 | \t\tkeyed \"one\" {}\n  | \t\t^",
+	},
+	"block map invalid body": {
+		`keyed "one" {
+			data = "bad"
+		}`,
+		blockTarget{Keyed: map[string]blockInner{}},
+		".keyed[\"one\"].data: invalid bool type string\nline(2) pos(11)\n2 | \t\t\tdata = \"bad\"\n  | \t\t\t       ^",
+	},
+	"block invalid body": {
+		`block "param" {
+			data = "bad"
+		}`,
+		blockTarget{Block: blockInner{P1: "param"}},
+		".block.data: invalid bool type string\nline(2) pos(11)\n2 | \t\t\tdata = \"bad\"\n  | \t\t\t       ^",
+	},
+	"block duplicate": {
+		`block "one" {}
+		block "two" {}`,
+		blockTarget{Block: blockInner{P1: "one"}},
+		".block: multiple \"block\" blocks found for field that is not a slice\nline(2) pos(3)\n2 | \t\tblock \"two\" {}\n  | \t\t^",
+	},
+}
+
+func TestUnmarshalBlocks(t *testing.T) {
+	for key, test := range blockUnmarshalTests {
+		t.Run(key, func(t *testing.T) {
+			tgt := blockTarget{}
+			err := icl.UnMarshalString(test.document, &tgt)
+
+			if test.error != "" {
+				require.NotNil(t, err)
+				require.Equal(t, test.error, err.Error())
+			} else {
+				require.Nil(t, err)
+			}
+
+			require.Equal(t, test.output, tgt)
+		})
+	}
+}
+
+func TestMarshalBlockMap(t *testing.T) {
+	target := blockTarget{
+		Keyed: map[string]blockInner{
+			"one":   {Data: true},
+			"three": {P1: "param"},
+		},
+		KeyPtr: map[string]*blockInner{"two": {}},
+	}
+
+	document, err := icl.MarshalString(target)
+	require.Nil(t, err)
+	require.Equal(t, `block "" {
+    data = false
+}
+
+keyed "one" {
+    data = true
+}
+keyed "three" "param" {
+    data = false
+}
+key_ptr "two" {
+    data = false
+}
+`, document)
+
+	var decoded blockTarget
+	require.Nil(t, icl.UnMarshalString(document, &decoded))
+	require.Equal(t, target, decoded)
+}
@@ -0,0 +1,189 @@
+package test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/indeedhat/icl"
+	"github.com/stretchr/testify/require"
+)
+
+type byteSizeTarget struct {
+	Size      icl.ByteSize            `icl:"size"`
+	SizePtr   *icl.ByteSize           `icl:"size_ptr"`
+	Default   icl.ByteSize            `icl:"default,default=64k"`
+	Buffers   []icl.ByteSize          `icl:"buffers"`
+	Limits    map[string]icl.ByteSize `icl:"limits"`
+	EnvSize   icl.ByteSize            `icl:"env_size"`
+	MaxBody   int64                   `icl:"max_body,bytes,default=1MiB"`
+	MaxUpload uint64                  `icl:"max_upload,bytes"`
+	Small     int8                    `icl:"small,bytes"`
+}
+
+var byteSizeUnmarshalTests = map[string]unmarshalTest{
+	"iec unit": {
+		`size = "512MiB"`,
+		byteSizeTarget{Size: 512 * icl.MiB, Default: 64 * icl.KiB, MaxBody: 1 << 20},
+		"",
+	},
+	"si unit": {
+		`size = "2GB"`,
+		byteSizeTarget{Size: 2 * icl.GB, Default: 64 * icl.KiB, MaxBody: 1 << 20},
+		"",
+	},
+	"short unit": {
+		`size = "64k"`,
+		byteSizeTarget{Size: 64 * icl.KiB, Default: 64 * icl.KiB, MaxBody: 1 << 20},
+		"",
+	},
+	"fractional unit": {
+		`size = "1.5 GiB"`,
+		byteSizeTarget{Size: 1536 * icl.MiB, Default: 64 * icl.KiB, MaxBody: 1 << 20},
+		"",
+	},
+	"unquoted unit": {
+		`size = 512MiB`,
+		byteSizeTarget{Size: 512 * icl.MiB, Default: 64 * icl.KiB, MaxBody: 1 << 20},
+		"",
+	},
+	"plain number": {
+		`size = 4096`,
+		byteSizeTarget{Size: 4096, Default: 64 * icl.KiB, MaxBody: 1 << 20},
+		"",
+	},
+	"hex number": {
+		`size = 0x1000`,
+		byteSizeTarget{Size: 4096, Default: 64 * icl.KiB, MaxBody: 1 << 20},
+		"",
+	},
+	"pointer": {
+		`size_ptr = "8m"`,
+		byteSizeTarget{SizePtr: ptr(8 * icl.MiB), Default: 64 * icl.KiB, MaxBody: 1 << 20},
+		"",
+	},
+	"slice": {
+		`buffers = ["4KiB", 8k, 1024]`,
+		byteSizeTarget{Buffers: []icl.ByteSize{4 * icl.KiB, 8 * icl.KiB, icl.KiB}, Default: 64 * icl.KiB, MaxBody: 1 << 20},
+		"",
+	},
+	"map": {
+		`limits = {memory: "2GiB", disk: 500GB}`,
+		byteSizeTarget{
+			Limits:  map[string]icl.ByteSize{"memory": 2 * icl.GiB, "disk": 500 * icl.GB},
+			Default: 64 * icl.KiB,
+			MaxBody: 1 << 20,
+		},
+		"",
+	},
+	"env": {
+		`env_size = env(ICL_TEST_SIZE)`,
+		byteSizeTarget{EnvSize: 256 * icl.MiB, Default: 64 * icl.KiB, MaxBody: 1 << 20},
+		"",
+	},
+	"bytes tag int": {
+		`max_body = "10MB"`,
+		byteSizeTarget{MaxBody: 10_000_000, Default: 64 * icl.KiB},
+		"",
+	},
+	"bytes tag uint": {
+		`max_upload = 1TiB`,
+		byteSizeTarget{MaxUpload: 1 << 40, Default: 64 * icl.KiB, MaxBody: 1 << 20},
+		"",
+	},
+	"invalid unit": {
+		`size = "12 parsecs"`,
+		byteSizeTarget{},
+		".size: invalid byte size \"12 parsecs\"\nline(1) pos(8)\n1 | size = \"12 parsecs\"\n  |        ^",
+	},
+	"partial byte": {
+		`size = "1.5B"`,
+		byteSizeTarget{},
+		".size: byte size \"1.5B\" is not a whole number of bytes\nline(1) pos(8)\n1 | size = \"1.5B\"\n  |        ^",
+	},
+	"overflow": {
+		`small = "1KiB"`,
+		byteSizeTarget{},
+		".small: byte size 1KiB overflows int8\nline(1) pos(9)\n1 | small = \"1KiB\"\n  |         ^",
+	},
+	"negative uint": {
+		`max_upload = "-1k"`,
+		byteSizeTarget{},
+		".max_upload: byte size -1KiB overflows uint64\nline(1) pos(14)\n1 | max_upload = \"-1k\"\n  |              ^",
+	},
+}
+
+func TestUnmarshalByteSize(t *testing.T) {
+	os.Setenv("ICL_TEST_SIZE", "256MiB")
+	defer os.Unsetenv("ICL_TEST_SIZE")
+
+	for key, test := range byteSizeUnmarshalTests {
+		t.Run(key, func(t *testing.T) {
+			tgt := byteSizeTarget{}
+			err := icl.UnMarshalString(test.document, &tgt)
+
+			if test.error != "" {
+				require.NotNil(t, err)
+				require.Equal(t, test.error, err.Error())
+			} else {
+				require.Nil(t, err)
+			}
+
+			require.Equal(t, test.output, tgt)
+		})
+	}
+}
+
+func TestMarshalByteSize(t *testing.T) {
+	target := byteSizeTarget{
+		Size:      512 * icl.MiB,
+		SizePtr:   ptr(2 * icl.GB),
+		Default:   64 * icl.KiB,
+		Buffers:   []icl.ByteSize{1536, 1000, 1023},
+		Limits:    map[string]icl.ByteSize{"memory": 1536 * icl.MiB},
+		MaxBody:   -10 * int64(icl.MB),
+		MaxUpload: 1 << 40,
+	}
+
+	document, err := icl.MarshalString(target)
+	require.Nil(t, err)
+	require.Equal(t, `size = "512MiB"
+size_ptr = "2GB"
+default = "64KiB"
+buffers = ["1536B", "1KB", "1023B"]
+limits = {
+    "memory": "1536MiB",
+}
+env_size = "0B"
+max_body = "-10MB"
+max_upload = "1TiB"
+small = "0B"
+`, document)
+
+	var decoded byteSizeTarget
+	require.Nil(t, icl.UnMarshalString(document, &decoded))
+	require.Equal(t, target, decoded)
+}
+
+func TestParseByteSize(t *testing.T) {
+	for input, expected := range map[string]icl.ByteSize{
+		"0":       0,
+		"1b":      1,
+		"1_024":   icl.KiB,
+		"1Ki":     icl.KiB,
+		"1kb":     icl.KB,
+		"3 EiB":   3 * icl.EiB,
+		"0.5MiB":  512 * icl.KiB,
+		"-2 GiB":  -2 * icl.GiB,
+		"7.5 TB":  7500 * icl.GB,
+		" 12MB  ": 12 * icl.MB,
+	} {
+		size, err := icl.ParseByteSize(input)
+		require.Nil(t, err, input)
+		require.Equal(t, expected, size, input)
+	}
+
+	for _, input := range []string{"", "MiB", "1 zb", "8EiB", "1..5k", "0x10"} {
+		_, err := icl.ParseByteSize(input)
+		require.NotNil(t, err, input)
+	}
+}
@@ -0,0 +1,141 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/indeedhat/icl"
+	"github.com/stretchr/testify/require"
+)
+
+type commentTest struct {
+	document string
+	expected string
+}
+
+var commentTests = map[string]commentTest{
+	"leading and inline assignment comments": {
+		`# the version
+# of the doc
+version = 1 # inline`,
+		`# the version
+# of the doc
+version = 1 # inline
+`,
+	},
+	"trailing document comments": {
+		`version = 1
+# trailing`,
+		`version = 1
+# trailing
+`,
+	},
+	"comment only document": {
+		`# only a comment`,
+		`# only a comment
+`,
+	},
+	"block comments": {
+		`# leading
+my_block "param" {
+	# inner leading
+	data = true # inner inline
+	# inner trailing
+} # block inline
+empty {
+	# dangling
+}`,
+		`# leading
+my_block "param" {
+    # inner leading
+    data = true # inner inline
+    # inner trailing
+} # block inline
+empty {
+    # dangling
+}
+`,
+	},
+	"map entry comments": {
+		`my_map = {
+	# leading
+	b: 2, # inline
+	a: 1 # no comma
+	# trailing
+}`,
+		`my_map = {
+    # leading
+    b: 2, # inline
+    a: 1, # no comma
+    # trailing
+}
+`,
+	},
+	"slice element comments": {
+		`my_slice = [
+	# leading
+	1, # inline
+	2,
+	# trailing
+]`,
+		`my_slice = [
+    # leading
+    1, # inline
+    2,
+    # trailing
+]
+`,
+	},
+	"slash comments": {
+		`// leading
+version = 1 // inline
+// trailing`,
+		`// leading
+version = 1 // inline
+// trailing
+`,
+	},
+	"multi line comments": {
+		`/*
+ * the version
+ */
+version = 1 /* inline */
+my_block {
+	/* inner
+	   leading */
+	data = true
+}`,
+		`/*
+ * the version
+ */
+version = 1 /* inline */
+my_block {
+    /* inner
+       leading */
+    data = true
+}
+`,
+	},
+	"slice without comments": {
+		`my_slice = [
+	1,
+	2,
+]`,
+		`my_slice = [1, 2]
+`,
+	},
+}
+
+func TestCommentRoundTrip(t *testing.T) {
+	for name, test := range commentTests {
+		t.Run(name, func(t *testing.T) {
+			a, err := icl.ParseString(test.document)
+			require.Nil(t, err)
+			require.Equal(t, test.expected, a.String())
+
+			// formatting the output again should be stable
+			a, err = icl.ParseString(a.String())
+			require.Nil(t, err)
+			require.Equal(t, test.expected, a.String())
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/indeedhat/icl"
+	"github.com/stretchr/testify/require"
+)
+
+type commentsTarget struct {
+	Version    int           `icl:"version"`
+	PreMyVar1  []string      `icl:".comments"`
+	MyVar1     string        `icl:"my_var_1"`
+	MyVar2     string        `icl:"my_var_2"`
+	PreBlock   []string      `icl:".comments"`
+	Block      commentsBlock `icl:"block"`
+	PostMyVar2 []string      `icl:".comments"`
+}
+
+type commentsBlock struct {
+	Comments map[int]string `icl:".comments"`
+	Data     bool           `icl:"data"`
+}
+
+const commentsDocument = `version = 1
+
+# These Comments will get
+# Unmarshaled into the structs PreMyVar1 field
+my_var_1 = "data"
+
+# these comments will be ignored
+my_var_2 = "data"
+
+# before the block
+block {
+    # inside the block
+    data = true # inline
+}
+
+# This comment will get Unmarshaled into the structs PostMyVar2 field
+`
+
+func TestUnmarshalComments(t *testing.T) {
+	tgt := commentsTarget{}
+	err := icl.UnMarshalString(commentsDocument, &tgt)
+	require.Nil(t, err)
+
+	require.Equal(t, commentsTarget{
+		Version:   1,
+		PreMyVar1: []string{"These Comments will get", "Unmarshaled into the structs PreMyVar1 field"},
+		MyVar1:    "data",
+		MyVar2:    "data",
+		PreBlock:  []string{"before the block"},
+		Block: commentsBlock{
+			Comments: map[int]string{12: "inside the block", 13: "inline"},
+			Data:     true,
+		},
+		PostMyVar2: []string{"This comment will get Unmarshaled into the structs PostMyVar2 field"},
+	}, tgt)
+}
+
+func TestUnmarshalSlashComments(t *testing.T) {
+	tgt := commentsTarget{}
+	err := icl.UnMarshalString(`version = 1
+
+/* These Comments will get
+   Unmarshaled into the structs PreMyVar1 field */
+my_var_1 = "data"
+my_var_2 = "data"
+
+// before the block
+block {
+    /* inside the block */
+    data = true // inline
+}
+`, &tgt)
+	require.Nil(t, err)
+
+	require.Equal(t, commentsTarget{
+		Version:   1,
+		PreMyVar1: []string{"These Comments will get\n   Unmarshaled into the structs PreMyVar1 field"},
+		MyVar1:    "data",
+		MyVar2:    "data",
+		PreBlock:  []string{"before the block"},
+		Block: commentsBlock{
+			Comments: map[int]string{10: "inside the block", 11: "inline"},
+			Data:     true,
+		},
+	}, tgt)
+}
+
+func TestMarshalComments(t *testing.T) {
+	output, err := icl.MarshalString(commentsTarget{
+		Version:   1,
+		PreMyVar1: []string{"before my_var_1"},
+		MyVar1:    "data",
+		MyVar2:    "data",
+		Block: commentsBlock{
+			Comments: map[int]string{2: "second", 1: "first"},
+			Data:     true,
+		},
+		PostMyVar2: []string{"at the end"},
+	})
+	require.Nil(t, err)
+
+	require.Equal(t, `version = 1
+# before my_var_1
+my_var_1 = "data"
+my_var_2 = "data"
+block {
+    # first
+    # second
+    data = true
+}
+# at the end
+`, output)
+}
+
+func TestCommentsMapLosesPosition(t *testing.T) {
+	tgt := commentsTarget{}
+	err := icl.UnMarshalString(`block {
+    data = true # inline
+    # after data
+}`, &tgt)
+	require.Nil(t, err)
+	require.Equal(t, map[int]string{2: "inline", 3: "after data"}, tgt.Block.Comments)
+
+	// map comment fields only keep the line numbers from the original document, they are all written before the
+	// next field when marshaling
+	output, err := icl.MarshalString(tgt)
+	require.Nil(t, err)
+	require.Equal(t, `version = 0
+my_var_1 = ""
+my_var_2 = ""
+block {
+    # inline
+    # after data
+    data = true
+}
+`, output)
+}
+
+func TestUnmarshalCommentsInvalidType(t *testing.T) {
+	tgt := struct {
+		Comments []int `icl:".comments"`
+	}{}
+
+	err := icl.UnMarshalString(`# comment`, &tgt)
+	require.NotNil(t, err)
+	require.Equal(t, ": .comments fields must be of type []string or map[int]string", err.Error())
+}
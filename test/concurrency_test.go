@@ -0,0 +1,83 @@
+package test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/indeedhat/icl"
+	"github.com/stretchr/testify/require"
+)
+
+type tenantConfig struct {
+	embeddedCommon
+	Region  string           `icl:"region,default=eu-west-1"`
+	Servers []embeddedServer `icl:"server"`
+}
+
+func TestConcurrentUnmarshal(t *testing.T) {
+	var wg sync.WaitGroup
+
+	errs := make([]error, 50)
+	targets := make([]tenantConfig, 50)
+
+	for i := range targets {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			document := fmt.Sprintf(`name = "tenant-%d"
+server "http" {
+    name = "web"
+    port = %d
+}`, i, 8000+i)
+
+			errs[i] = icl.UnMarshalString(document, &targets[i])
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i, tgt := range targets {
+		require.Nil(t, errs[i])
+		require.Equal(t, tenantConfig{
+			embeddedCommon: embeddedCommon{Name: fmt.Sprintf("tenant-%d", i), Enabled: true},
+			Region:         "eu-west-1",
+			Servers: []embeddedServer{{
+				embeddedParams: embeddedParams{Kind: "http"},
+				embeddedCommon: embeddedCommon{Name: "web", Enabled: true},
+				Port:           8000 + i,
+			}},
+		}, tgt)
+	}
+}
+
+func TestConcurrentMarshal(t *testing.T) {
+	var wg sync.WaitGroup
+
+	errs := make([]error, 50)
+	documents := make([]string, 50)
+
+	for i := range documents {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			documents[i], errs[i] = icl.MarshalString(tenantConfig{
+				embeddedCommon: embeddedCommon{Name: fmt.Sprintf("tenant-%d", i)},
+				Region:         "us-east-1",
+			})
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i, document := range documents {
+		require.Nil(t, errs[i])
+		require.Equal(t, fmt.Sprintf(`name = "tenant-%d"
+enabled = false
+region = "us-east-1"
+
+`, i), document)
+	}
+}
@@ -0,0 +1,107 @@
+package test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/indeedhat/icl"
+	"github.com/stretchr/testify/require"
+)
+
+type decodeErrorsTarget struct {
+	Name   string             `icl:"name,required"`
+	Count  int8               `icl:"count"`
+	Ports  []int              `icl:"ports"`
+	Labels map[string]string  `icl:"labels"`
+	Server decodeErrorsServer `icl:"server"`
+}
+
+type decodeErrorsServer struct {
+	Enabled bool `icl:"enabled"`
+}
+
+const decodeErrorsDocument = `count = 300
+ports = [80, "443", 8080]
+labels = {env: "prod", tier: 3}
+unknown = true
+server {
+	enabled = "yes"
+}`
+
+func TestUnmarshalCollectErrors(t *testing.T) {
+	tgt := decodeErrorsTarget{}
+	err := icl.UnMarshalStringWithOptions(decodeErrorsDocument, &tgt, icl.DecodeOptions{
+		CollectErrors:         true,
+		DisallowUnknownFields: true,
+	})
+
+	var decodeErrs icl.DecodeErrors
+	require.True(t, errors.As(err, &decodeErrs))
+	require.Len(t, decodeErrs, 6)
+
+	expected := []struct {
+		path     string
+		expected reflect.Kind
+		found    icl.TokenType
+	}{
+		{".count", reflect.Int8, icl.TknNumber},
+		{".ports", reflect.Int, icl.TknString},
+		{".labels", reflect.String, icl.TknNumber},
+		{".unknown", reflect.Invalid, ""},
+		{".server.enabled", reflect.Bool, icl.TknString},
+		{".name", reflect.Invalid, ""},
+	}
+
+	for i, e := range expected {
+		require.Equal(t, e.path, decodeErrs[i].Path)
+		require.Equal(t, e.expected, decodeErrs[i].Expected)
+		require.Equal(t, e.found, decodeErrs[i].Found)
+	}
+
+	// valid values are still decoded around the failures
+	require.Equal(t, []int{80, 8080}, tgt.Ports)
+	require.Equal(t, map[string]string{"env": "prod"}, tgt.Labels)
+
+	var decodeErr *icl.DecodeError
+	require.True(t, errors.As(err, &decodeErr))
+	require.Equal(t, ".count", decodeErr.Path)
+	require.Equal(t, ".name: required field is missing", decodeErrs[5].Error())
+}
+
+func TestUnmarshalStopsOnFirstError(t *testing.T) {
+	tgt := decodeErrorsTarget{}
+	err := icl.UnMarshalString(decodeErrorsDocument, &tgt)
+
+	var decodeErr *icl.DecodeError
+	require.True(t, errors.As(err, &decodeErr))
+	require.Equal(t, ".count", decodeErr.Path)
+	require.Equal(t, reflect.Int8, decodeErr.Expected)
+	require.Equal(t, icl.TknNumber, decodeErr.Found)
+	require.Nil(t, tgt.Ports)
+}
+
+func TestUnmarshalMismatchErrorKinds(t *testing.T) {
+	tests := map[string]struct {
+		document string
+		expected reflect.Kind
+		found    icl.TokenType
+	}{
+		"slice":     {`ports = 5`, reflect.Slice, icl.TknNumber},
+		"map":       {`labels = [1]`, reflect.Map, icl.TknLBracket},
+		"struct":    {`server = 5`, reflect.Struct, icl.TknNumber},
+		"map block": {`labels { env = "prod" }`, reflect.Map, icl.TknIdent},
+	}
+
+	for key, test := range tests {
+		t.Run(key, func(t *testing.T) {
+			tgt := decodeErrorsTarget{}
+			err := icl.UnMarshalString(test.document, &tgt)
+
+			var decodeErr *icl.DecodeError
+			require.True(t, errors.As(err, &decodeErr))
+			require.Equal(t, test.expected, decodeErr.Expected)
+			require.Equal(t, test.found, decodeErr.Found)
+		})
+	}
+}
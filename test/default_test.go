@@ -0,0 +1,144 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/indeedhat/icl"
+	"github.com/stretchr/testify/require"
+)
+
+type defaultTarget struct {
+	S     string         `icl:"s,default=some value, with a comma"`
+	Sp    *string        `icl:"sp,default=pointer"`
+	B     bool           `icl:"b,default=true"`
+	I     int            `icl:"i,default=-12"`
+	I8    int8           `icl:"i8,default=8"`
+	U     uint           `icl:"u,default=12"`
+	F     float64        `icl:"f.2,default=3.14"`
+	Ip    *int           `icl:"ip,default=7"`
+	Ss    []string       `icl:"ss,default=[\"a\", \"b\"]"`
+	Is    []int          `icl:"is,default=[1, 2, 3]"`
+	M     map[string]int `icl:"m,default={one: 1}"`
+	Block defaultBlock   `icl:"block"`
+	NoTag string         `icl:"no_tag"`
+}
+
+type defaultBlock struct {
+	Data string `icl:"data,default=block default"`
+}
+
+var defaultUnmarshalTests = map[string]unmarshalTest{
+	"all defaults": {
+		``,
+		defaultTarget{
+			S:     "some value, with a comma",
+			Sp:    ptr("pointer"),
+			B:     true,
+			I:     -12,
+			I8:    8,
+			U:     12,
+			F:     3.14,
+			Ip:    ptr(7),
+			Ss:    []string{"a", "b"},
+			Is:    []int{1, 2, 3},
+			M:     map[string]int{"one": 1},
+			Block: defaultBlock{Data: "block default"},
+		},
+		"",
+	},
+	"document overrides defaults": {
+		`s = "set"
+		b = false
+		i = 1
+		is = []
+		block {
+			data = "set"
+		}`,
+		defaultTarget{
+			S:     "set",
+			Sp:    ptr("pointer"),
+			B:     false,
+			I:     1,
+			I8:    8,
+			U:     12,
+			F:     3.14,
+			Ip:    ptr(7),
+			Ss:    []string{"a", "b"},
+			M:     map[string]int{"one": 1},
+			Block: defaultBlock{Data: "set"},
+		},
+		"",
+	},
+	"null does not get a default": {
+		`ip = null`,
+		defaultTarget{
+			S:     "some value, with a comma",
+			Sp:    ptr("pointer"),
+			B:     true,
+			I:     -12,
+			I8:    8,
+			U:     12,
+			F:     3.14,
+			Ss:    []string{"a", "b"},
+			Is:    []int{1, 2, 3},
+			M:     map[string]int{"one": 1},
+			Block: defaultBlock{Data: "block default"},
+		},
+		"",
+	},
+}
+
+func TestUnmarshalDefaults(t *testing.T) {
+	for key, test := range defaultUnmarshalTests {
+		t.Run(key, func(t *testing.T) {
+			tgt := defaultTarget{}
+			err := icl.UnMarshalString(test.document, &tgt)
+
+			if test.error != "" {
+				require.NotNil(t, err)
+				require.Equal(t, test.error, err.Error())
+			} else {
+				require.Nil(t, err)
+			}
+
+			require.Equal(t, test.output, tgt)
+		})
+	}
+}
+
+func TestUnmarshalInvalidDefault(t *testing.T) {
+	tgt := struct {
+		I int `icl:"i,default=nope"`
+	}{}
+
+	err := icl.UnMarshalString(``, &tgt)
+	require.NotNil(t, err)
+	require.Equal(t, ".i: invalid default value: invalid node type IDENT", err.Error())
+}
+
+func TestMarshalOmitDefaults(t *testing.T) {
+	v := defaultTarget{
+		S:     "some value, with a comma",
+		Sp:    ptr("not the default"),
+		B:     true,
+		I:     -12,
+		I8:    8,
+		U:     12,
+		F:     3.14,
+		Ip:    ptr(7),
+		Ss:    []string{"a", "b"},
+		Is:    []int{1, 2},
+		M:     map[string]int{"one": 1},
+		Block: defaultBlock{Data: "block default"},
+		NoTag: "value",
+	}
+
+	output, err := icl.MarshalStringWithOptions(v, icl.EncodeOptions{OmitDefaults: true})
+	require.Nil(t, err)
+	require.Equal(t, `sp = "not the default"
+is = [1, 2]
+block {
+}
+no_tag = "value"
+`, output)
+}
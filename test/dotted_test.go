@@ -0,0 +1,230 @@
+package test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/indeedhat/icl"
+	"github.com/stretchr/testify/require"
+)
+
+type dottedTLS struct {
+	Enabled bool   `icl:"enabled"`
+	Cert    string `icl:"cert"`
+}
+
+type dottedListener struct {
+	Name string `icl:".param"`
+	Port int    `icl:"port"`
+}
+
+type dottedServer struct {
+	Port      int              `icl:"port"`
+	TLS       dottedTLS        `icl:"tls"`
+	Listeners []dottedListener `icl:"listener"`
+}
+
+type dottedTarget struct {
+	Server         dottedServer      `icl:"server"`
+	Cache          *dottedTLS        `icl:"cache"`
+	Labels         map[string]string `icl:"labels"`
+	MaxConnections int               `icl:"max-connections"`
+	Size           int               `icl:"größe"`
+	Name           string            `icl:"名前"`
+	Tags           []string          `icl:"tags"`
+}
+
+var dottedUnmarshalTests = map[string]unmarshalTest{
+	"dotted keys": {
+		`server.port = 80
+server.tls.enabled = true
+server.tls.cert = "server.pem"`,
+		dottedTarget{Server: dottedServer{Port: 80, TLS: dottedTLS{Enabled: true, Cert: "server.pem"}}},
+		"",
+	},
+	"dotted keys in block": {
+		`server {
+	port = 80
+	tls.enabled = true
+	tls.cert = "server.pem"
+}`,
+		dottedTarget{Server: dottedServer{Port: 80, TLS: dottedTLS{Enabled: true, Cert: "server.pem"}}},
+		"",
+	},
+	"dotted block": {
+		`server.listener "http" {
+	port = 80
+}
+server.listener "https" {
+	port = 443
+}`,
+		dottedTarget{Server: dottedServer{Listeners: []dottedListener{{"http", 80}, {"https", 443}}}},
+		"",
+	},
+	"dotted pointer": {
+		`cache.enabled = true`,
+		dottedTarget{Cache: &dottedTLS{Enabled: true}},
+		"",
+	},
+	"dotted map": {
+		`labels.team = "ops"
+labels.tier = "backend"`,
+		dottedTarget{Labels: map[string]string{"team": "ops", "tier": "backend"}},
+		"",
+	},
+	"dotted keys split": {
+		`server.tls.cert = "a"
+labels.team = "ops"
+server.port = 1`,
+		dottedTarget{Server: dottedServer{Port: 1, TLS: dottedTLS{Cert: "a"}}, Labels: map[string]string{"team": "ops"}},
+		"",
+	},
+	"dotted keys after block": {
+		`server {
+	port = 80
+	tls {
+		enabled = true
+	}
+}
+max-connections = 5
+server.tls.cert = "server.pem"`,
+		dottedTarget{Server: dottedServer{Port: 80, TLS: dottedTLS{Enabled: true, Cert: "server.pem"}}, MaxConnections: 5},
+		"",
+	},
+	"block after dotted keys": {
+		`server.tls.enabled = true
+server {
+	port = 80
+	tls.cert = "server.pem"
+}`,
+		dottedTarget{Server: dottedServer{Port: 80, TLS: dottedTLS{Enabled: true, Cert: "server.pem"}}},
+		"",
+	},
+	"repeated explicit blocks": {
+		`cache {
+	enabled = true
+}
+cache {
+	cert = "a"
+}`,
+		dottedTarget{Cache: &dottedTLS{Enabled: true}},
+		".cache: multiple \"cache\" blocks found for field that is not a slice\nline(4) pos(1)\n4 | cache {\n  | ^",
+	},
+	"dotted key error": {
+		`server.tls.enabled = "yes"`,
+		dottedTarget{},
+		".server.tls.enabled: invalid bool type string\nline(1) pos(22)\n1 | server.tls.enabled = \"yes\"\n  |                      ^",
+	},
+	"dotted key into value": {
+		`max-connections.limit = 100`,
+		dottedTarget{},
+		".max-connections: cannot decode block into int\nline(1) pos(1)\n1 | max-connections.limit = 100\n  | ^",
+	},
+	"block into slice of values": {
+		`tags {
+	name = "a"
+}`,
+		dottedTarget{},
+		".tags: cannot decode block into []string\nline(1) pos(1)\n1 | tags {\n  | ^",
+	},
+	"hyphenated key": {
+		`max-connections = 100`,
+		dottedTarget{MaxConnections: 100},
+		"",
+	},
+	"unicode keys": {
+		`größe = 12
+名前 = "テスト"`,
+		dottedTarget{Size: 12, Name: "テスト"},
+		"",
+	},
+	"unicode key error": {
+		`größe = "12"`,
+		dottedTarget{},
+		".größe: invalid int type string\nline(1) pos(9)\n1 | größe = \"12\"\n  |         ^",
+	},
+}
+
+func TestUnmarshalDotted(t *testing.T) {
+	for key, test := range dottedUnmarshalTests {
+		t.Run(key, func(t *testing.T) {
+			tgt := dottedTarget{}
+			err := icl.UnMarshalString(test.document, &tgt)
+
+			if test.error != "" {
+				require.NotNil(t, err)
+				require.Equal(t, test.error, err.Error())
+			} else {
+				require.Nil(t, err)
+			}
+
+			require.Equal(t, test.output, tgt)
+		})
+	}
+}
+
+func TestUnmarshalReaderDotted(t *testing.T) {
+	for _, key := range []string{"dotted keys", "dotted keys split", "dotted keys after block", "block after dotted keys"} {
+		test := dottedUnmarshalTests[key]
+
+		tgt := dottedTarget{}
+		require.Nil(t, icl.UnMarshalReader(strings.NewReader(test.document), &tgt), key)
+		require.Equal(t, test.output, tgt, key)
+	}
+}
+
+func TestUnmarshalDottedMismatch(t *testing.T) {
+	document := `max-connections.limit = 100
+tags {
+	name = "a"
+}`
+
+	tgt := dottedTarget{}
+	err := icl.UnMarshalReaderWithOptions(strings.NewReader(document), &tgt, icl.DecodeOptions{CollectErrors: true})
+
+	var decodeErrs icl.DecodeErrors
+	require.True(t, errors.As(err, &decodeErrs))
+	require.Len(t, decodeErrs, 2)
+	require.Equal(t, ".max-connections", decodeErrs[0].Path)
+	require.Equal(t, reflect.Int, decodeErrs[0].Expected)
+	require.Equal(t, icl.TknIdent, decodeErrs[0].Found)
+	require.Equal(t, ".tags", decodeErrs[1].Path)
+	require.Equal(t, reflect.Slice, decodeErrs[1].Expected)
+	require.Equal(t, icl.TknIdent, decodeErrs[1].Found)
+}
+
+func TestDottedValue(t *testing.T) {
+	a, err := icl.ParseString(`server {
+	port = 80
+}
+timeout = "1s"
+server.tls.cert = "a"`)
+	require.Nil(t, err)
+
+	value, err := a.Value()
+	require.Nil(t, err)
+	require.Equal(t, map[string]any{
+		"server":  map[string]any{"port": int64(80), "tls": map[string]any{"cert": "a"}},
+		"timeout": "1s",
+	}, value.Interface())
+}
+
+func TestParseDotted(t *testing.T) {
+	a, err := icl.ParseString(`# tls config
+server.tls.enabled = true # inline
+server.tls.cert = "server.pem"
+server.port = 80`)
+	require.Nil(t, err)
+
+	require.Equal(t, `server {
+    tls {
+        # tls config
+        enabled = true # inline
+        cert = "server.pem"
+    }
+    port = 80
+}
+`, a.String())
+}
@@ -0,0 +1,171 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/indeedhat/icl"
+	"github.com/stretchr/testify/require"
+)
+
+type embeddedCommon struct {
+	Name    string `icl:"name"`
+	Enabled bool   `icl:"enabled,default=true"`
+}
+
+type embeddedParams struct {
+	Kind string `icl:".param"`
+}
+
+type EmbeddedLabels struct {
+	Owner string `icl:"owner"`
+	Team  string `icl:"team"`
+}
+
+type embeddedMeta struct {
+	Name string `icl:"name"`
+}
+
+type embeddedServer struct {
+	embeddedParams
+	embeddedCommon
+	Port int `icl:"port"`
+}
+
+type embeddedTarget struct {
+	embeddedCommon
+	*EmbeddedLabels
+	Owner   string           `icl:"owner"`
+	Tagged  embeddedCommon   `icl:"tagged"`
+	Servers []embeddedServer `icl:"server"`
+}
+
+type embeddedConflict struct {
+	embeddedCommon
+	embeddedMeta
+}
+
+var embeddedUnmarshalTests = map[string]unmarshalTest{
+	"flattened fields": {
+		`name = "app"
+		enabled = false`,
+		embeddedTarget{embeddedCommon: embeddedCommon{Name: "app"}, Tagged: embeddedCommon{Enabled: true}},
+		"",
+	},
+	"flattened defaults": {
+		`name = "app"`,
+		embeddedTarget{embeddedCommon: embeddedCommon{Name: "app", Enabled: true}, Tagged: embeddedCommon{Enabled: true}},
+		"",
+	},
+	"shallow field wins": {
+		`owner = "ops"`,
+		embeddedTarget{
+			embeddedCommon: embeddedCommon{Enabled: true},
+			Owner:          "ops",
+			Tagged:         embeddedCommon{Enabled: true},
+		},
+		"",
+	},
+	"tagged embedded struct is a block": {
+		`tagged {
+			name = "inner"
+		}`,
+		embeddedTarget{
+			embeddedCommon: embeddedCommon{Enabled: true},
+			Tagged:         embeddedCommon{Name: "inner", Enabled: true},
+		},
+		"",
+	},
+	"embedded pointer field": {
+		`team = "infra"`,
+		embeddedTarget{
+			embeddedCommon: embeddedCommon{Enabled: true},
+			EmbeddedLabels: &EmbeddedLabels{Team: "infra"},
+			Tagged:         embeddedCommon{Enabled: true},
+		},
+		"",
+	},
+	"flattened block fields": {
+		`server "http" {
+			name = "web"
+			port = 80
+		}`,
+		embeddedTarget{
+			embeddedCommon: embeddedCommon{Enabled: true},
+			Tagged:         embeddedCommon{Enabled: true},
+			Servers: []embeddedServer{{
+				embeddedParams: embeddedParams{Kind: "http"},
+				embeddedCommon: embeddedCommon{Name: "web", Enabled: true},
+				Port:           80,
+			}},
+		},
+		"",
+	},
+}
+
+func TestUnmarshalEmbedded(t *testing.T) {
+	for key, test := range embeddedUnmarshalTests {
+		t.Run(key, func(t *testing.T) {
+			tgt := embeddedTarget{}
+			err := icl.UnMarshalString(test.document, &tgt)
+
+			if test.error != "" {
+				require.NotNil(t, err)
+				require.Equal(t, test.error, err.Error())
+			} else {
+				require.Nil(t, err)
+			}
+
+			require.Equal(t, test.output, tgt)
+		})
+	}
+}
+
+func TestUnmarshalEmbeddedConflict(t *testing.T) {
+	tgt := embeddedConflict{}
+	err := icl.UnMarshalStringWithOptions(`name = "app"
+enabled = false`, &tgt, icl.DecodeOptions{DisallowUnknownFields: true})
+
+	require.NotNil(t, err)
+	require.Equal(t, "icl: unknown fields\n.name -- [line(1) pos(1)]", err.Error())
+	require.Equal(t, embeddedConflict{}, tgt)
+}
+
+func TestUnmarshalEmbeddedPointer(t *testing.T) {
+	type target struct {
+		*EmbeddedLabels
+	}
+
+	tgt := target{}
+	require.Nil(t, icl.UnMarshalString(``, &tgt))
+	require.Nil(t, tgt.EmbeddedLabels)
+
+	require.Nil(t, icl.UnMarshalString(`owner = "ops"`, &tgt))
+	require.Equal(t, &EmbeddedLabels{Owner: "ops"}, tgt.EmbeddedLabels)
+}
+
+func TestMarshalEmbedded(t *testing.T) {
+	document, err := icl.MarshalString(embeddedTarget{
+		embeddedCommon: embeddedCommon{Name: "app", Enabled: true},
+		Owner:          "ops",
+		Servers: []embeddedServer{{
+			embeddedParams: embeddedParams{Kind: "http"},
+			embeddedCommon: embeddedCommon{Name: "web"},
+			Port:           80,
+		}},
+	})
+
+	require.Nil(t, err)
+	require.Equal(t, `name = "app"
+enabled = true
+owner = "ops"
+tagged {
+    name = ""
+    enabled = false
+}
+server "http" {
+    name = "web"
+    enabled = false
+    port = 80
+}
+`, document)
+}
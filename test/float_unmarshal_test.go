@@ -1,6 +1,7 @@
 package test
 
 import (
+	"math"
 	"testing"
 
 	"github.com/indeedhat/icl"
@@ -26,7 +27,7 @@ var floatUnmarshalTests = map[string]unmarshalTest{
 	"float32 bad type": {
 		`f32 = "bad"`,
 		floatTarget{},
-		".f32: invalid float32 type string\nline(0) pos(8)",
+		".f32: invalid float32 type string\nline(1) pos(7)\n1 | f32 = \"bad\"\n  |       ^",
 	},
 	"float64 valid": {
 		`f64 = 1283.1`,
@@ -38,13 +39,74 @@ var floatUnmarshalTests = map[string]unmarshalTest{
 		floatTarget{F64: 128},
 		"",
 	},
+	"float64 exponent": {
+		`f64 = 1.5e-3`,
+		floatTarget{F64: 0.0015},
+		"",
+	},
+	"float64 underscores": {
+		`f64 = 1_000.25`,
+		floatTarget{F64: 1000.25},
+		"",
+	},
+	"float64 hex": {
+		`f64 = 0x1p-2`,
+		floatTarget{F64: 0.25},
+		"",
+	},
+	"float infinity": {
+		`f32 = inf
+		f64 = -inf`,
+		floatTarget{F32: float32(math.Inf(1)), F64: math.Inf(-1)},
+		"",
+	},
 	"float64 bad type": {
 		`f64 = "bad"`,
 		floatTarget{},
-		".f64: invalid float64 type string\nline(0) pos(8)",
+		".f64: invalid float64 type string\nline(1) pos(7)\n1 | f64 = \"bad\"\n  |       ^",
 	},
 }
 
+func TestUnmarshalFloatNaN(t *testing.T) {
+	tgt := floatTarget{}
+	require.Nil(t, icl.UnMarshalString(`f64 = nan`, &tgt))
+	require.True(t, math.IsNaN(tgt.F64))
+}
+
+func TestMarshalFloatSpecialValues(t *testing.T) {
+	type specialTarget struct {
+		Inf    float64   `icl:"inf"`
+		NegInf float32   `icl:"neg_inf"`
+		NaN    float64   `icl:"nan.2"`
+		List   []float64 `icl:"list"`
+	}
+
+	target := specialTarget{
+		Inf:    math.Inf(1),
+		NegInf: float32(math.Inf(-1)),
+		NaN:    math.NaN(),
+		List:   []float64{math.Inf(-1), math.NaN(), 1.5},
+	}
+
+	document, err := icl.MarshalString(target)
+	require.Nil(t, err)
+	require.Equal(t, `inf = inf
+neg_inf = -inf
+nan = nan
+list = [-inf, nan, 1.5]
+`, document)
+
+	var decoded specialTarget
+	require.Nil(t, icl.UnMarshalString(document, &decoded))
+	require.Equal(t, math.Inf(1), decoded.Inf)
+	require.Equal(t, float32(math.Inf(-1)), decoded.NegInf)
+	require.True(t, math.IsNaN(decoded.NaN))
+	require.Len(t, decoded.List, 3)
+	require.Equal(t, math.Inf(-1), decoded.List[0])
+	require.True(t, math.IsNaN(decoded.List[1]))
+	require.Equal(t, 1.5, decoded.List[2])
+}
+
 func TestUnmarshalFloat(t *testing.T) {
 	for key, test := range floatUnmarshalTests {
 		t.Run(key, func(t *testing.T) {
@@ -82,7 +144,7 @@ var floatPtrUnmarshalTests = map[string]unmarshalTest{
 	"float32 bad type": {
 		`f32 = "bad"`,
 		floatPtrTarget{},
-		".f32: invalid float32 type string\nline(0) pos(8)",
+		".f32: invalid float32 type string\nline(1) pos(7)\n1 | f32 = \"bad\"\n  |       ^",
 	},
 	"float64 valid": {
 		`f64 = 1283.1`,
@@ -97,7 +159,7 @@ var floatPtrUnmarshalTests = map[string]unmarshalTest{
 	"float64 bad type": {
 		`f64 = "bad"`,
 		floatPtrTarget{},
-		".f64: invalid float64 type string\nline(0) pos(8)",
+		".f64: invalid float64 type string\nline(1) pos(7)\n1 | f64 = \"bad\"\n  |       ^",
 	},
 }
 
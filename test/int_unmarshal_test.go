@@ -28,10 +28,50 @@ var intUnmarshalTests = map[string]unmarshalTest{
 		intTarget{I: math.MinInt},
 		"",
 	},
+	"int hex": {
+		`i = 0x1F`,
+		intTarget{I: 31},
+		"",
+	},
+	"int octal": {
+		`i16 = 0o755`,
+		intTarget{I16: 0o755},
+		"",
+	},
+	"int binary": {
+		`i8 = -0b1010`,
+		intTarget{I8: -10},
+		"",
+	},
+	"int underscores": {
+		`i32 = 1_000_000`,
+		intTarget{I32: 1000000},
+		"",
+	},
+	"int leading zero is decimal": {
+		`i = 0755`,
+		intTarget{I: 755},
+		"",
+	},
+	"int exponent": {
+		`i64 = 1e6`,
+		intTarget{I64: 1000000},
+		"",
+	},
+	"int fractional exponent": {
+		`i = 1.5e0`,
+		intTarget{},
+		".i: strconv.ParseInt: parsing \"1.5e0\": invalid syntax\nline(1) pos(5)\n1 | i = 1.5e0\n  |     ^",
+	},
+	"int exponent out of range": {
+		`i8 = 1e3`,
+		intTarget{},
+		".i8: strconv.ParseInt: parsing \"1000\": value out of range\nline(1) pos(6)\n1 | i8 = 1e3\n  |      ^",
+	},
 	"int bad type": {
 		`i = "bad"`,
 		intTarget{I: 0},
-		".i: invalid int type string\nline(0) pos(6)",
+		".i: invalid int type string\nline(1) pos(5)\n1 | i = \"bad\"\n  |     ^",
 	},
 	"int8 valid": {
 		`i8 = 127`,
@@ -46,12 +86,12 @@ var intUnmarshalTests = map[string]unmarshalTest{
 	"int8 bad type": {
 		`i8 = "bad"`,
 		intTarget{I8: 0},
-		".i8: invalid int8 type string\nline(0) pos(7)",
+		".i8: invalid int8 type string\nline(1) pos(6)\n1 | i8 = \"bad\"\n  |      ^",
 	},
 	"int8 too large": {
 		`i8 = 129`,
 		intTarget{I8: 0},
-		".i8: strconv.ParseInt: parsing \"129\": value out of range\nline(0) pos(5)",
+		".i8: strconv.ParseInt: parsing \"129\": value out of range\nline(1) pos(6)\n1 | i8 = 129\n  |      ^",
 	},
 	"int16 valid": {
 		`i16 = 32767`,
@@ -66,12 +106,12 @@ var intUnmarshalTests = map[string]unmarshalTest{
 	"int16 bad type": {
 		`i16 = "bad"`,
 		intTarget{I16: 0},
-		".i16: invalid int16 type string\nline(0) pos(8)",
+		".i16: invalid int16 type string\nline(1) pos(7)\n1 | i16 = \"bad\"\n  |       ^",
 	},
 	"int16 too large": {
 		`i16 = 32768`,
 		intTarget{I16: 0},
-		".i16: strconv.ParseInt: parsing \"32768\": value out of range\nline(0) pos(6)",
+		".i16: strconv.ParseInt: parsing \"32768\": value out of range\nline(1) pos(7)\n1 | i16 = 32768\n  |       ^",
 	},
 	"int32 valid": {
 		`i32 = 2147483647`,
@@ -86,12 +126,12 @@ var intUnmarshalTests = map[string]unmarshalTest{
 	"int32 bad type": {
 		`i32 = "bad"`,
 		intTarget{I32: 0},
-		".i32: invalid int32 type string\nline(0) pos(8)",
+		".i32: invalid int32 type string\nline(1) pos(7)\n1 | i32 = \"bad\"\n  |       ^",
 	},
 	"int32 too large": {
 		`i32 = 2147483648`,
 		intTarget{I32: 0},
-		".i32: strconv.ParseInt: parsing \"2147483648\": value out of range\nline(0) pos(6)",
+		".i32: strconv.ParseInt: parsing \"2147483648\": value out of range\nline(1) pos(7)\n1 | i32 = 2147483648\n  |       ^",
 	},
 	"int64 valid": {
 		`i64 = 9223372036854775807`,
@@ -106,7 +146,7 @@ var intUnmarshalTests = map[string]unmarshalTest{
 	"int64 bad type": {
 		`i64 = "bad"`,
 		intTarget{I16: 0},
-		".i64: invalid int64 type string\nline(0) pos(8)",
+		".i64: invalid int64 type string\nline(1) pos(7)\n1 | i64 = \"bad\"\n  |       ^",
 	},
 }
 
@@ -150,7 +190,7 @@ var intPtrUnmarshalTests = map[string]unmarshalTest{
 	"int bad type": {
 		`i = "bad"`,
 		intPtrTarget{},
-		".i: invalid int type string\nline(0) pos(6)",
+		".i: invalid int type string\nline(1) pos(5)\n1 | i = \"bad\"\n  |     ^",
 	},
 	"int8 valid": {
 		`i8 = 127`,
@@ -165,12 +205,12 @@ var intPtrUnmarshalTests = map[string]unmarshalTest{
 	"int8 bad type": {
 		`i8 = "bad"`,
 		intPtrTarget{},
-		".i8: invalid int8 type string\nline(0) pos(7)",
+		".i8: invalid int8 type string\nline(1) pos(6)\n1 | i8 = \"bad\"\n  |      ^",
 	},
 	"int8 too large": {
 		`i8 = 129`,
 		intPtrTarget{},
-		".i8: strconv.ParseInt: parsing \"129\": value out of range\nline(0) pos(5)",
+		".i8: strconv.ParseInt: parsing \"129\": value out of range\nline(1) pos(6)\n1 | i8 = 129\n  |      ^",
 	},
 	"int16 valid": {
 		`i16 = 32767`,
@@ -185,12 +225,12 @@ var intPtrUnmarshalTests = map[string]unmarshalTest{
 	"int16 bad type": {
 		`i16 = "bad"`,
 		intPtrTarget{},
-		".i16: invalid int16 type string\nline(0) pos(8)",
+		".i16: invalid int16 type string\nline(1) pos(7)\n1 | i16 = \"bad\"\n  |       ^",
 	},
 	"int16 too large": {
 		`i16 = 32768`,
 		intPtrTarget{},
-		".i16: strconv.ParseInt: parsing \"32768\": value out of range\nline(0) pos(6)",
+		".i16: strconv.ParseInt: parsing \"32768\": value out of range\nline(1) pos(7)\n1 | i16 = 32768\n  |       ^",
 	},
 	"int32 valid": {
 		`i32 = 2147483647`,
@@ -205,12 +245,12 @@ var intPtrUnmarshalTests = map[string]unmarshalTest{
 	"int32 bad type": {
 		`i32 = "bad"`,
 		intPtrTarget{},
-		".i32: invalid int32 type string\nline(0) pos(8)",
+		".i32: invalid int32 type string\nline(1) pos(7)\n1 | i32 = \"bad\"\n  |       ^",
 	},
 	"int32 too large": {
 		`i32 = 2147483648`,
 		intPtrTarget{},
-		".i32: strconv.ParseInt: parsing \"2147483648\": value out of range\nline(0) pos(6)",
+		".i32: strconv.ParseInt: parsing \"2147483648\": value out of range\nline(1) pos(7)\n1 | i32 = 2147483648\n  |       ^",
 	},
 	"int64 valid": {
 		`i64 = 9223372036854775807`,
@@ -225,7 +265,7 @@ var intPtrUnmarshalTests = map[string]unmarshalTest{
 	"int64 bad type": {
 		`i64 = "bad"`,
 		intPtrTarget{},
-		".i64: invalid int64 type string\nline(0) pos(8)",
+		".i64: invalid int64 type string\nline(1) pos(7)\n1 | i64 = \"bad\"\n  |       ^",
 	},
 }
 
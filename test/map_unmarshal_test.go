@@ -8,9 +8,11 @@ import (
 )
 
 type mapTarget struct {
-	IntMap     map[string]int     `icl:"int_map"`
-	Float64Map map[string]float64 `icl:"float64_map"`
-	StringMap  map[string]string  `icl:"string_map"`
+	IntMap     map[string]int            `icl:"int_map"`
+	Float64Map map[string]float64        `icl:"float64_map"`
+	StringMap  map[string]string         `icl:"string_map"`
+	NestedMap  map[string]map[string]int `icl:"nested_map"`
+	SliceMap   map[string][]string       `icl:"slice_map"`
 }
 
 var mapUnmarshalTests = map[string]unmarshalTest{
@@ -22,7 +24,7 @@ var mapUnmarshalTests = map[string]unmarshalTest{
 	"int map invalid value type": {
 		`int_map = {"one": "bad", "two": 2}`,
 		mapTarget{IntMap: map[string]int{}},
-		".int_map: invalid int type string\nline(0) pos(20)",
+		".int_map: invalid int type string\nline(1) pos(19)\n1 | int_map = {\"one\": \"bad\", \"two\": 2}\n  |                   ^",
 	},
 	"int map empty": {
 		`int_map = {}`,
@@ -41,8 +43,23 @@ var mapUnmarshalTests = map[string]unmarshalTest{
 	},
 	"string map invalid key type": {
 		`string_map = {1: "value1"}`,
-		mapTarget{StringMap: map[string]string{}},
-		".string_map: Map keys must be a string\nline(0) pos(19)",
+		mapTarget{},
+		"icl: parse error\ntoken type NUMBER is not allowed here -- [line(1) pos(15)]\n1 | string_map = {1: \"value1\"}\n  |               ^",
+	},
+	"nested map valid": {
+		`nested_map = {a: {one: 1}, b: {two: 2, three: 3}}`,
+		mapTarget{NestedMap: map[string]map[string]int{"a": {"one": 1}, "b": {"two": 2, "three": 3}}},
+		"",
+	},
+	"nested map invalid": {
+		`nested_map = {a: {one: "bad"}}`,
+		mapTarget{NestedMap: map[string]map[string]int{}},
+		".nested_map: invalid int type string\nline(1) pos(24)\n1 | nested_map = {a: {one: \"bad\"}}\n  |                        ^",
+	},
+	"slice map valid": {
+		`slice_map = {a: ["one", "two"], b: []}`,
+		mapTarget{SliceMap: map[string][]string{"a": {"one", "two"}, "b": nil}},
+		"",
 	},
 }
 
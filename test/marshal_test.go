@@ -95,6 +95,7 @@ type MarshalTarget struct {
 	Sb    SubBlock            `icl:"sb"`
 	Sbp   *SubBlock           `icl:"sbp"`
 	Sbs   []SubBlock          `icl:"sbs"`
+	Sbm   map[string]SubBlock `icl:"sbm"`
 	Sbw   SubBlockWrapper     `icl:"sbw"`
 }
 
@@ -320,6 +321,12 @@ sbs "param1" "param2" {
 sbs "param1" "param2" {
     data = "data3"
 }
+sbm "one" "param1" "param2" {
+    data = "data"
+}
+sbm "two" "param1" "param2" {
+    data = "data2"
+}
 sbw {
     sb "param1" "param2" {
         data = "data"
@@ -451,6 +458,18 @@ var marshalTarget = MarshalTarget{
 			Data: "data3",
 		},
 	},
+	Sbm: map[string]SubBlock{
+		"two": {
+			P1:   "param1",
+			P2:   "param2",
+			Data: "data2",
+		},
+		"one": {
+			P1:   "param1",
+			P2:   "param2",
+			Data: "data",
+		},
+	},
 	Sbw: SubBlockWrapper{
 		Sb: SubBlock{
 			P1:   "param1",
@@ -488,3 +507,44 @@ func TestMarshalDocument(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, expectedMarshalDocument, document)
 }
+
+type nestedMarshalTarget struct {
+	Matrix [][]int                   `icl:"matrix"`
+	Routes []map[string]string       `icl:"routes"`
+	Flags  map[string]map[string]int `icl:"flags"`
+	Groups map[string][]string       `icl:"groups"`
+}
+
+const expectedNestedMarshalDocument = `matrix = [[1, 2], [3]]
+routes = [{
+    "path": "/",
+}, {
+    "path": "/api",
+}]
+flags = {
+    "beta": {
+        "rollout": 50,
+    },
+}
+groups = {
+    "admin": ["alice", "bob"],
+    "users": ["carol"],
+}
+`
+
+func TestMarshalNestedCollections(t *testing.T) {
+	target := nestedMarshalTarget{
+		Matrix: [][]int{{1, 2}, {3}},
+		Routes: []map[string]string{{"path": "/"}, {"path": "/api"}},
+		Flags:  map[string]map[string]int{"beta": {"rollout": 50}},
+		Groups: map[string][]string{"users": {"carol"}, "admin": {"alice", "bob"}},
+	}
+
+	document, err := icl.MarshalString(target)
+	require.Nil(t, err)
+	require.Equal(t, expectedNestedMarshalDocument, document)
+
+	var decoded nestedMarshalTarget
+	require.Nil(t, icl.UnMarshalString(document, &decoded))
+	require.Equal(t, target, decoded)
+}
@@ -0,0 +1,242 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/indeedhat/icl"
+	"github.com/stretchr/testify/require"
+)
+
+type s3Backend struct {
+	Bucket string `icl:"bucket"`
+}
+
+type fsBackend struct {
+	Path string `icl:"path"`
+}
+
+// backend picks its concrete type from the first param of the block
+type backend struct {
+	Impl any
+}
+
+func (b *backend) UnmarshalICL(node icl.Node) error {
+	block, ok := node.(*icl.BlockNode)
+	if !ok || len(block.Parameters) == 0 {
+		return errors.New("backend must be a block with a type param")
+	}
+
+	switch block.Parameters[0].Literal {
+	case "s3":
+		b.Impl = &s3Backend{}
+	case "fs":
+		b.Impl = &fsBackend{}
+	default:
+		return fmt.Errorf("unknown backend %q", block.Parameters[0].Literal)
+	}
+
+	return icl.Ast{Nodes: block.Body.Nodes}.Unmarshal(b.Impl)
+}
+
+func (b backend) MarshalICL() (icl.Node, error) {
+	var kind string
+	switch b.Impl.(type) {
+	case *s3Backend:
+		kind = "s3"
+	case *fsBackend:
+		kind = "fs"
+	default:
+		return nil, errors.New("unknown backend")
+	}
+
+	e, err := icl.NewEncoder(b.Impl)
+	if err != nil {
+		return nil, err
+	}
+
+	ast, err := e.Encode(b.Impl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &icl.BlockNode{
+		Parameters: []icl.Token{{Type: icl.TknString, Literal: kind}},
+		Body:       &icl.BlockBodyNode{Nodes: ast.Nodes},
+	}, nil
+}
+
+// port accepts either a number or a well known service name
+type port int
+
+func (p *port) UnmarshalICL(node icl.Node) error {
+	switch n := node.(type) {
+	case *icl.NumberNode:
+		_, err := fmt.Sscan(n.Value, (*int)(p))
+		return err
+	case *icl.StringNode:
+		switch n.Value {
+		case "http":
+			*p = 80
+		case "https":
+			*p = 443
+		default:
+			return fmt.Errorf("unknown service %q", n.Value)
+		}
+		return nil
+	}
+
+	return errors.New("port must be a number or service name")
+}
+
+func (p port) MarshalICL() (icl.Node, error) {
+	return &icl.NumberNode{Value: fmt.Sprint(int(p))}, nil
+}
+
+// endpoint is a struct that is represented as a single "host:port" string
+type endpoint struct {
+	Host string
+	Port int
+}
+
+func (e *endpoint) UnmarshalICL(node icl.Node) error {
+	str, ok := node.(*icl.StringNode)
+	if !ok {
+		return errors.New("endpoint must be a string")
+	}
+
+	host, p, found := strings.Cut(str.Value, ":")
+	if !found {
+		return fmt.Errorf("endpoint %q is missing a port", str.Value)
+	}
+
+	e.Host = host
+	_, err := fmt.Sscan(p, &e.Port)
+	return err
+}
+
+func (e endpoint) MarshalICL() (icl.Node, error) {
+	return &icl.StringNode{Value: fmt.Sprintf("%s:%d", e.Host, e.Port)}, nil
+}
+
+type marshalerTarget struct {
+	Primary   backend            `icl:"primary"`
+	Fallback  *backend           `icl:"fallback"`
+	Backends  []backend          `icl:"backend"`
+	Named     map[string]backend `icl:"named"`
+	Port      port               `icl:"port"`
+	Ports     []port             `icl:"ports"`
+	Endpoints []endpoint         `icl:"endpoints"`
+}
+
+var marshalerUnmarshalTests = map[string]unmarshalTest{
+	"block field": {
+		`primary "s3" {
+			bucket = "assets"
+		}`,
+		marshalerTarget{Primary: backend{Impl: &s3Backend{Bucket: "assets"}}},
+		"",
+	},
+	"block pointer field": {
+		`fallback "fs" {
+			path = "/tmp"
+		}`,
+		marshalerTarget{Fallback: &backend{Impl: &fsBackend{Path: "/tmp"}}},
+		"",
+	},
+	"block slice": {
+		`backend "s3" {
+			bucket = "assets"
+		}
+		backend "fs" {
+			path = "/tmp"
+		}`,
+		marshalerTarget{Backends: []backend{
+			{Impl: &s3Backend{Bucket: "assets"}},
+			{Impl: &fsBackend{Path: "/tmp"}},
+		}},
+		"",
+	},
+	"block map without key param": {
+		`named "cold" "s3" {
+			bucket = "archive"
+		}`,
+		marshalerTarget{Named: map[string]backend{"cold": {Impl: &s3Backend{Bucket: "archive"}}}},
+		"",
+	},
+	"block error": {
+		`primary "ftp" {}`,
+		marshalerTarget{},
+		".primary: unknown backend \"ftp\"\nline(1) pos(1)\n1 | primary \"ftp\" {}\n  | ^",
+	},
+	"assignment": {
+		`port = "https"`,
+		marshalerTarget{Port: 443},
+		"",
+	},
+	"slice elements": {
+		`ports = [8080, "http"]`,
+		marshalerTarget{Ports: []port{8080, 80}},
+		"",
+	},
+	"assignment error": {
+		`port = true`,
+		marshalerTarget{},
+		".port: port must be a number or service name\nline(1) pos(8)\n1 | port = true\n  |        ^",
+	},
+}
+
+func TestUnmarshalUnmarshaler(t *testing.T) {
+	for key, test := range marshalerUnmarshalTests {
+		t.Run(key, func(t *testing.T) {
+			tgt := marshalerTarget{}
+			err := icl.UnMarshalString(test.document, &tgt)
+
+			if test.error != "" {
+				require.NotNil(t, err)
+				require.Equal(t, test.error, err.Error())
+			} else {
+				require.Nil(t, err)
+			}
+
+			require.Equal(t, test.output, tgt)
+		})
+	}
+}
+
+func TestMarshalMarshaler(t *testing.T) {
+	target := marshalerTarget{
+		Primary:   backend{Impl: &s3Backend{Bucket: "assets"}},
+		Fallback:  &backend{Impl: &fsBackend{Path: "/tmp"}},
+		Backends:  []backend{{Impl: &fsBackend{Path: "/var"}}},
+		Named:     map[string]backend{"cold": {Impl: &s3Backend{Bucket: "archive"}}},
+		Port:      443,
+		Ports:     []port{80, 8080},
+		Endpoints: []endpoint{{Host: "localhost", Port: 80}, {Host: "example.com", Port: 443}},
+	}
+
+	document, err := icl.MarshalString(target)
+	require.Nil(t, err)
+	require.Equal(t, `primary "s3" {
+    bucket = "assets"
+}
+fallback "fs" {
+    path = "/tmp"
+}
+backend "fs" {
+    path = "/var"
+}
+named "cold" "s3" {
+    bucket = "archive"
+}
+port = 443
+ports = [80, 8080]
+endpoints = ["localhost:80", "example.com:443"]
+`, document)
+
+	var decoded marshalerTarget
+	require.Nil(t, icl.UnMarshalString(document, &decoded))
+	require.Equal(t, target, decoded)
+}
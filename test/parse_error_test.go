@@ -0,0 +1,123 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/indeedhat/icl"
+	"github.com/stretchr/testify/require"
+)
+
+type parseErrorTest struct {
+	document    string
+	diagnostics []icl.Diagnostic
+}
+
+var parseErrorTests = map[string]parseErrorTest{
+	"valid document": {
+		`version = 1
+		my_map = {key: "value",}
+		my_slice = [1, 2, 3,]
+		my_block "param" {
+			# comment
+			data = true
+		}`,
+		nil,
+	},
+	"bad map key": {
+		`my_map = {1: "value"}`,
+		[]icl.Diagnostic{
+			{Message: "token type NUMBER is not allowed here", Line: 1, Pos: 11},
+		},
+	},
+	"missing map colon": {
+		`my_map = {key "value"}`,
+		[]icl.Diagnostic{
+			{Message: "Unexpected token type: expected(:) found(STRING)", Line: 1, Pos: 15},
+		},
+	},
+	"unterminated slice": {
+		`my_slice = [1, 2
+		other = true`,
+		[]icl.Diagnostic{
+			{Message: "Unexpected token type: expected(]) found(IDENT)", Line: 2, Pos: 3},
+		},
+	},
+	"diagnostic per line": {
+		`first = {1: 2}
+		second = [1 2]`,
+		[]icl.Diagnostic{
+			{Message: "token type NUMBER is not allowed here", Line: 1, Pos: 10},
+			{Message: "Unexpected token type: expected(]) found(NUMBER)", Line: 2, Pos: 15},
+		},
+	},
+	"error inside one line block": {
+		`server { a = [1 2] }
+		port = 1`,
+		[]icl.Diagnostic{
+			{Message: "Unexpected token type: expected(]) found(NUMBER)", Line: 1, Pos: 17},
+		},
+	},
+	"error inside nested map in one line block": {
+		`server { a = {x: 1 2} b = 2 }
+		port = 1`,
+		[]icl.Diagnostic{
+			{Message: "Unexpected token type: expected(}) found(NUMBER)", Line: 1, Pos: 20},
+		},
+	},
+	"nested env macro": {
+		`key = env(env(`,
+		[]icl.Diagnostic{
+			{Message: "Unexpected token type: expected(IDENT) found(EOF)", Line: 1, Pos: 15},
+		},
+	},
+	"env macro with env argument": {
+		`key = env(env(NAME))`,
+		[]icl.Diagnostic{
+			{Message: "env() macro expects an identifier", Line: 1, Pos: 7},
+		},
+	},
+}
+
+func TestParseErrorKeepsBlockClose(t *testing.T) {
+	for _, document := range []string{"server { a = [1 2] }\nport = 1", "server { a = {x: 1 2} b = 2 }\nport = 1"} {
+		a, err := icl.ParseStringWithOptions(document, icl.ParseOptions{Strict: true})
+		require.Len(t, diagnosticMessages(t, err), 1, document)
+
+		require.Len(t, a.Nodes, 2, document)
+		require.Equal(t, "server", a.Nodes[0].TokenLiteral())
+		require.Equal(t, "port", a.Nodes[1].TokenLiteral())
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for name, test := range parseErrorTests {
+		t.Run(name, func(t *testing.T) {
+			_, err := icl.ParseString(test.document)
+			if test.diagnostics == nil {
+				require.Nil(t, err)
+				return
+			}
+
+			var parseErr *icl.ParseError
+			require.True(t, errors.As(err, &parseErr))
+			require.Len(t, parseErr.Diagnostics, len(test.diagnostics))
+
+			for i, expected := range test.diagnostics {
+				require.Equal(t, expected.Message, parseErr.Diagnostics[i].Message)
+				require.Equal(t, expected.Line, parseErr.Diagnostics[i].Line)
+				require.Equal(t, expected.Pos, parseErr.Diagnostics[i].Pos)
+			}
+		})
+	}
+}
+
+func TestUnmarshalRefusesParseErrors(t *testing.T) {
+	tgt := stringTarget{}
+	err := icl.UnMarshalString(`s = "value"
+	sp = {1: "bad"}`, &tgt)
+
+	var parseErr *icl.ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Equal(t, stringTarget{}, tgt)
+}
@@ -0,0 +1,215 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/indeedhat/icl"
+	"github.com/stretchr/testify/require"
+)
+
+type readerTarget struct {
+	Header  []string          `icl:".comments"`
+	Name    string            `icl:"name,required"`
+	Port    int               `icl:"port,default=8080"`
+	Servers []blockInner      `icl:"server"`
+	Labels  map[string]string `icl:"labels"`
+	Footer  []string          `icl:".comments"`
+}
+
+var readerUnmarshalTests = map[string]unmarshalTest{
+	"assignments and blocks": {
+		`# header
+name = "app"
+server "a" {
+	data = true
+}
+labels = {team: "ops"}
+# footer`,
+		readerTarget{
+			Header:  []string{"header"},
+			Name:    "app",
+			Port:    8080,
+			Servers: []blockInner{{P1: "a", Data: true}},
+			Labels:  map[string]string{"team": "ops"},
+			Footer:  []string{"footer"},
+		},
+		"",
+	},
+	"repeated blocks": {
+		`name = "app"
+server "a" {
+	data = true
+}
+server "c" {
+	data = false
+}
+port = 9000`,
+		readerTarget{
+			Name:    "app",
+			Port:    9000,
+			Servers: []blockInner{{P1: "a", Data: true}, {P1: "c"}},
+		},
+		"",
+	},
+	"missing field": {
+		`port = 9000`,
+		readerTarget{Port: 9000},
+		"icl: missing required fields\n.name",
+	},
+	"decode error": {
+		`name = "app"
+port = "http"`,
+		readerTarget{Name: "app"},
+		".port: invalid int type string\nline(2) pos(8)\n2 | port = \"http\"\n  |        ^",
+	},
+	"decode error after block comment": {
+		`/* header
+   spans lines */
+name = "app" // inline
+port = "http"`,
+		readerTarget{Name: "app"},
+		".port: invalid int type string\nline(4) pos(8)\n4 | port = \"http\"\n  |        ^",
+	},
+	"parse error": {
+		`name = "app"
+port = `,
+		readerTarget{Name: "app"},
+		"icl: parse error\nno prefix parser found for EOF -- [line(2) pos(8)]\n2 | port = \n  |        ^",
+	},
+}
+
+func TestUnmarshalReader(t *testing.T) {
+	for key, test := range readerUnmarshalTests {
+		t.Run(key, func(t *testing.T) {
+			tgt := readerTarget{}
+			err := icl.UnMarshalReader(strings.NewReader(test.document), &tgt)
+
+			if test.error != "" {
+				require.NotNil(t, err)
+				require.Equal(t, test.error, err.Error())
+			} else {
+				require.Nil(t, err)
+			}
+
+			require.Equal(t, test.output, tgt)
+		})
+	}
+}
+
+func TestUnmarshalReaderMatchesString(t *testing.T) {
+	document := benchDocument(200)
+
+	var expected, actual benchConfig
+	require.Nil(t, icl.UnMarshalString(document, &expected))
+	require.Nil(t, icl.NewDecoderFromReader(iotest.OneByteReader(strings.NewReader(document))).Decode(&actual))
+
+	require.Equal(t, expected, actual)
+}
+
+func TestUnmarshalReaderError(t *testing.T) {
+	readErr := errors.New("connection reset")
+	r := io.MultiReader(strings.NewReader("name = \"app\"\n"), iotest.ErrReader(readErr))
+
+	tgt := readerTarget{}
+	err := icl.UnMarshalReader(r, &tgt)
+
+	require.ErrorIs(t, err, readErr)
+	require.Equal(t, "app", tgt.Name)
+}
+
+func TestUnmarshalReaderReleasesBlocks(t *testing.T) {
+	type item struct {
+		N int `icl:"n"`
+	}
+
+	type target struct {
+		Items []item `icl:"item"`
+	}
+
+	readErr := errors.New("connection reset")
+	r := io.MultiReader(strings.NewReader(`item {
+	n = 1
+}
+item {
+	n = 2
+}
+item {
+	n = 3
+}
+`), iotest.ErrReader(readErr))
+
+	tgt := target{}
+	err := icl.UnMarshalReader(r, &tgt)
+
+	// only the last item can still have dotted keys merged into it so every earlier item is decoded as it is replaced
+	require.ErrorIs(t, err, readErr)
+	require.Equal(t, []item{{1}, {2}}, tgt.Items)
+}
+
+func TestParserNodes(t *testing.T) {
+	p := icl.NewParser(icl.NewLexerFromReader(strings.NewReader(`name = "app"
+server "a" {}
+# trailing
+port = 80
+port = ]`)))
+
+	var (
+		names []string
+		errs  []error
+	)
+
+	for node, err := range p.Nodes() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		names = append(names, node.TokenLiteral())
+	}
+
+	require.Equal(t, []string{"name", "server", "port"}, names)
+	require.Len(t, errs, 1)
+	require.Equal(t, "no prefix parser found for ] -- [line(5) pos(8)]\n5 | port = ]\n  |        ^", errs[0].Error())
+}
+
+func TestParserNodesStopEarly(t *testing.T) {
+	p := icl.NewParser(icl.NewLexerFromReader(strings.NewReader("a = 1\nb = 2\nc = 3")))
+
+	var names []string
+	for node := range p.Nodes() {
+		names = append(names, node.TokenLiteral())
+		if len(names) == 2 {
+			break
+		}
+	}
+
+	require.Equal(t, []string{"a", "b"}, names)
+}
+
+func TestUnmarshalReaderLongLine(t *testing.T) {
+	document := "labels = {" + strings.Repeat(`key: "value", `, 5000) + "}\ntags = [" + strings.Repeat(`"abcdefg", `, 5000) + "5]"
+
+	type target struct {
+		Labels map[string]string `icl:"labels"`
+		Tags   []string          `icl:"tags"`
+	}
+
+	var expected, actual target
+	expectedErr := icl.UnMarshalString(document, &expected)
+	err := icl.NewDecoderFromReader(iotest.OneByteReader(strings.NewReader(document))).Decode(&actual)
+
+	require.NotNil(t, err)
+	require.Equal(t, expected, actual)
+	require.Len(t, actual.Tags, 5000)
+
+	// the start of the line is no longer held by the reader so the excerpt only contains the end of the line
+	require.True(t, strings.HasPrefix(expectedErr.Error(), ".tags: invalid type NUMBER : string\nline(2) pos(55009)\n2 | tags = ["))
+	require.True(t, strings.HasPrefix(err.Error(), ".tags: invalid type NUMBER : string\nline(2) pos(55009)\n2 | ..."))
+
+	lines := strings.Split(err.Error(), "\n")
+	require.Equal(t, strings.Index(lines[2], "5]"), strings.Index(lines[3], "^"))
+}
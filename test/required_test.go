@@ -0,0 +1,103 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/indeedhat/icl"
+	"github.com/stretchr/testify/require"
+)
+
+type requiredTarget struct {
+	Version int            `icl:"version,required"`
+	Name    string         `icl:"name,required,default=ignored"`
+	Server  requiredServer `icl:"server"`
+	Backup  *requiredTLS   `icl:"backup"`
+}
+
+type requiredServer struct {
+	Host string      `icl:"host,required"`
+	TLS  requiredTLS `icl:"tls"`
+}
+
+type requiredTLS struct {
+	Cert string `icl:"cert,required"`
+	Key  string `icl:"key"`
+}
+
+func TestUnmarshalRequired(t *testing.T) {
+	tgt := requiredTarget{}
+	err := icl.UnMarshalString(`version = 1
+name = "name"
+server {
+	host = "localhost"
+	tls {
+		cert = "cert.pem"
+	}
+}`, &tgt)
+
+	require.Nil(t, err)
+	require.Equal(t, requiredTarget{
+		Version: 1,
+		Name:    "name",
+		Server: requiredServer{
+			Host: "localhost",
+			TLS:  requiredTLS{Cert: "cert.pem"},
+		},
+	}, tgt)
+}
+
+func TestUnmarshalRequiredMissing(t *testing.T) {
+	tgt := requiredTarget{}
+	err := icl.UnMarshalString(`version = 1
+server {
+	tls {
+		key = "key.pem"
+	}
+}`, &tgt)
+
+	var missingErr *icl.MissingFieldsError
+	require.True(t, errors.As(err, &missingErr))
+	require.Equal(t, []icl.MissingField{
+		{Path: ".server.tls.cert", Block: ".server.tls", Line: 3},
+		{Path: ".server.host", Block: ".server", Line: 2},
+		{Path: ".name", Block: "", Line: 0},
+	}, missingErr.Fields)
+	require.Equal(t, `icl: missing required fields
+.server.tls.cert -- [block(.server.tls) line(3)]
+.server.host -- [block(.server) line(2)]
+.name`, err.Error())
+}
+
+func TestUnmarshalRequiredMissingBlock(t *testing.T) {
+	tgt := requiredTarget{}
+	err := icl.UnMarshalString(`version = 1
+name = "name"`, &tgt)
+
+	var missingErr *icl.MissingFieldsError
+	require.True(t, errors.As(err, &missingErr))
+	require.Equal(t, []icl.MissingField{
+		{Path: ".server.host", Block: ".server", Line: 0},
+		{Path: ".server.tls.cert", Block: ".server.tls", Line: 0},
+	}, missingErr.Fields)
+	require.Equal(t, `icl: missing required fields
+.server.host -- [block(.server)]
+.server.tls.cert -- [block(.server.tls)]`, err.Error())
+}
+
+func TestUnmarshalRequiredMissingNestedBlock(t *testing.T) {
+	tgt := requiredTarget{}
+	err := icl.UnMarshalString(`version = 1
+name = "name"
+server {
+	host = "localhost"
+}`, &tgt)
+
+	var missingErr *icl.MissingFieldsError
+	require.True(t, errors.As(err, &missingErr))
+	require.Equal(t, []icl.MissingField{
+		{Path: ".server.tls.cert", Block: ".server.tls", Line: 3},
+	}, missingErr.Fields)
+	require.Equal(t, `icl: missing required fields
+.server.tls.cert -- [block(.server.tls) line(3)]`, err.Error())
+}
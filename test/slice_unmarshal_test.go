@@ -8,9 +8,11 @@ import (
 )
 
 type sliceTarget struct {
-	IntSlice     []int     `icl:"int_slice"`
-	Float64Slice []float64 `icl:"float64_slice"`
-	StringSlice  []string  `icl:"string_slice"`
+	IntSlice     []int            `icl:"int_slice"`
+	Float64Slice []float64        `icl:"float64_slice"`
+	StringSlice  []string         `icl:"string_slice"`
+	NestedSlice  [][]int          `icl:"nested_slice"`
+	MapSlice     []map[string]int `icl:"map_slice"`
 }
 
 var sliceUnmarshalTests = map[string]unmarshalTest{
@@ -22,7 +24,7 @@ var sliceUnmarshalTests = map[string]unmarshalTest{
 	"int slice invalid type": {
 		`int_slice = ["bad", 2, 3]`,
 		sliceTarget{},
-		".int_slice: invalid int type string\nline(0) pos(15)",
+		".int_slice: invalid int type string\nline(1) pos(14)\n1 | int_slice = [\"bad\", 2, 3]\n  |              ^",
 	},
 	"int slice empty": {
 		`int_slice = []`,
@@ -37,7 +39,7 @@ var sliceUnmarshalTests = map[string]unmarshalTest{
 	"float64 slice invalid": {
 		`float64_slice = ["bad", 2.2, 3.3]`,
 		sliceTarget{},
-		".float64_slice: invalid float64 type string\nline(0) pos(19)",
+		".float64_slice: invalid float64 type string\nline(1) pos(18)\n1 | float64_slice = [\"bad\", 2.2, 3.3]\n  |                  ^",
 	},
 	"string slice valid": {
 		`string_slice = ["a", "b", "c"]`,
@@ -47,7 +49,27 @@ var sliceUnmarshalTests = map[string]unmarshalTest{
 	"string slice invalid": {
 		`string_slice = [1, 2, 3]`,
 		sliceTarget{},
-		".string_slice: invalid type NUMBER : string\nline(0) pos(16)",
+		".string_slice: invalid type NUMBER : string\nline(1) pos(17)\n1 | string_slice = [1, 2, 3]\n  |                 ^",
+	},
+	"nested slice valid": {
+		`nested_slice = [[1, 2], [], [3]]`,
+		sliceTarget{NestedSlice: [][]int{{1, 2}, nil, {3}}},
+		"",
+	},
+	"nested slice invalid": {
+		`nested_slice = [[1, 2], 3]`,
+		sliceTarget{NestedSlice: [][]int{{1, 2}}},
+		".nested_slice: node is not a slice\nline(1) pos(25)\n1 | nested_slice = [[1, 2], 3]\n  |                         ^",
+	},
+	"map slice valid": {
+		`map_slice = [{a: 1}, {a: 2, b: 3}]`,
+		sliceTarget{MapSlice: []map[string]int{{"a": 1}, {"a": 2, "b": 3}}},
+		"",
+	},
+	"map slice invalid": {
+		`map_slice = [{a: "bad"}]`,
+		sliceTarget{},
+		".map_slice: invalid int type string\nline(1) pos(18)\n1 | map_slice = [{a: \"bad\"}]\n  |                  ^",
 	},
 }
 
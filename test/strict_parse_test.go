@@ -0,0 +1,117 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/indeedhat/icl"
+	"github.com/stretchr/testify/require"
+)
+
+type strictParseTest struct {
+	document string
+	lenient  []string
+	strict   []string
+}
+
+var strictParseTests = map[string]strictParseTest{
+	"valid document": {
+		`version = 1
+		my_block "param" {
+			data = true
+		}`,
+		nil,
+		nil,
+	},
+	"stray expression": {
+		`version = 1
+		"stray"`,
+		nil,
+		[]string{"unexpected STRING, expected an assignment or block"},
+	},
+	"missing assignment": {
+		`version = 1
+		typo true`,
+		nil,
+		[]string{"Unexpected token type: expected(=) found(TRUE)"},
+	},
+	"illegal token": {
+		`version = 1
+		@
+		data = true`,
+		[]string{"no prefix parser found for ILLEGAL"},
+		[]string{`illegal token "@"`},
+	},
+	"illegal multi byte token": {
+		`version = 1
+		€
+		data = true`,
+		[]string{"no prefix parser found for ILLEGAL"},
+		[]string{`illegal token "€"`},
+	},
+	"unterminated block comment": {
+		"a = 1\n/* x\nb = 2",
+		[]string{"unterminated block comment"},
+		[]string{"unterminated block comment"},
+	},
+	"unterminated block comment as value": {
+		`version = /* 1`,
+		[]string{"unterminated block comment"},
+		[]string{"unterminated block comment"},
+	},
+	"unterminated heredoc": {
+		"a = 1\nb = <<EOT\nc = 2",
+		[]string{`unterminated heredoc, closing marker "EOT" not found`},
+		[]string{`unterminated heredoc, closing marker "EOT" not found`},
+	},
+	"stray slash": {
+		`version = 1
+		/ data = true`,
+		[]string{"no prefix parser found for ILLEGAL"},
+		[]string{`illegal token "/"`},
+	},
+	"illegal token between assignments": {
+		"a = 1\n$\nb = 2",
+		[]string{"no prefix parser found for ILLEGAL"},
+		[]string{`illegal token "$"`},
+	},
+	"illegal token before key": {
+		`.a = 1`,
+		[]string{"no prefix parser found for ILLEGAL"},
+		[]string{`illegal token "."`},
+	},
+	"unterminated block": {
+		`my_block {
+			data = true`,
+		nil,
+		[]string{"unterminated block"},
+	},
+}
+
+func TestStrictParsing(t *testing.T) {
+	for name, test := range strictParseTests {
+		t.Run(name, func(t *testing.T) {
+			_, err := icl.ParseString(test.document)
+			require.Equal(t, test.lenient, diagnosticMessages(t, err))
+
+			_, err = icl.ParseStringWithOptions(test.document, icl.ParseOptions{Strict: true})
+			require.Equal(t, test.strict, diagnosticMessages(t, err))
+		})
+	}
+}
+
+func diagnosticMessages(t *testing.T, err error) []string {
+	if err == nil {
+		return nil
+	}
+
+	var parseErr *icl.ParseError
+	require.True(t, errors.As(err, &parseErr))
+
+	var messages []string
+	for _, d := range parseErr.Diagnostics {
+		messages = append(messages, d.Message)
+	}
+
+	return messages
+}
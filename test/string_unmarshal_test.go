@@ -19,10 +19,137 @@ var stringUnmarshalTests = map[string]unmarshalTest{
 		stringTarget{S: "a string"},
 		"",
 	},
+	"string escaped quotes": {
+		`s = "say \"hi\" twice"`,
+		stringTarget{S: `say "hi" twice`},
+		"",
+	},
+	"string single quoted": {
+		`s = 'a "quoted" string'`,
+		stringTarget{S: `a "quoted" string`},
+		"",
+	},
+	"string empty": {
+		`s = ""`,
+		stringTarget{S: ""},
+		"",
+	},
+	"string escapes": {
+		`s = "tab\there\nnew line \\ \u00e9 \x41"`,
+		stringTarget{S: "tab\there\nnew line \\ \u00e9 A"},
+		"",
+	},
+	"string escaped quotes in single quotes": {
+		`s = 'it\'s \"fine\"'`,
+		stringTarget{S: `it's "fine"`},
+		"",
+	},
+	"string escaped backslash before quote": {
+		`s = "C:\\"`,
+		stringTarget{S: `C:\`},
+		"",
+	},
+	"string unknown escape": {
+		`s = "C:\data"`,
+		stringTarget{S: `C:\data`},
+		"",
+	},
+	"string truncated unicode escape": {
+		`s = "x\u12"
+sp = "next"`,
+		stringTarget{S: `x\u12`, SP: ptr("next")},
+		"",
+	},
+	"string truncated long unicode escape": {
+		`s = "\U0001F60"
+sp = "next"`,
+		stringTarget{S: `\U0001F60`, SP: ptr("next")},
+		"",
+	},
+	"string truncated hex escape": {
+		`s = '\x4'
+sp = "next"`,
+		stringTarget{S: `\x4`, SP: ptr("next")},
+		"",
+	},
+	"string invalid unicode escape": {
+		`s = "\uZZ12 ok"`,
+		stringTarget{S: `\uZZ12 ok`},
+		"",
+	},
+	"string escape at end": {
+		`s = "tail\u"`,
+		stringTarget{S: `tail\u`},
+		"",
+	},
+	"string unterminated": {
+		`s = "abc`,
+		stringTarget{},
+		"icl: parse error\nno prefix parser found for ILLEGAL -- [line(1) pos(5)]\n1 | s = \"abc\n  |     ^",
+	},
+	"raw string": {
+		"s = `C:\\data\n\\d+ \"quoted\"`",
+		stringTarget{S: "C:\\data\n\\d+ \"quoted\""},
+		"",
+	},
+	"heredoc": {
+		`s = <<EOT
+SELECT *
+  FROM users
+EOT`,
+		stringTarget{S: "SELECT *\n  FROM users\n"},
+		"",
+	},
+	"heredoc strip indent": {
+		`s = <<-PEM
+			-----BEGIN CERTIFICATE-----
+			MIIBszCCAVmgAwIBAgIUd
+			  indented
+			-----END CERTIFICATE-----
+		PEM
+		sp = "after"`,
+		stringTarget{
+			S:  "-----BEGIN CERTIFICATE-----\nMIIBszCCAVmgAwIBAgIUd\n  indented\n-----END CERTIFICATE-----\n",
+			SP: ptr("after"),
+		},
+		"",
+	},
+	"heredoc empty": {
+		`s = <<EOT
+EOT`,
+		stringTarget{S: ""},
+		"",
+	},
+	"heredoc unterminated": {
+		`s = <<EOT
+never closed`,
+		stringTarget{},
+		"icl: parse error\nunterminated heredoc, closing marker \"EOT\" not found -- [line(1) pos(5)]\n1 | s = <<EOT\n  |     ^",
+	},
+	"heredoc strip indent unterminated": {
+		`s = <<-EOT
+	never closed
+	EOF`,
+		stringTarget{},
+		"icl: parse error\nunterminated heredoc, closing marker \"EOT\" not found -- [line(1) pos(5)]\n1 | s = <<-EOT\n  |     ^",
+	},
+	"heredoc closing marker trailing space": {
+		"s = <<EOT\nline\nEOT \t\nsp = \"after\"",
+		stringTarget{S: "line\n", SP: ptr("after")},
+		"",
+	},
+	"heredoc indented closing marker": {
+		`s = <<EOT
+  indented
+  EOT
+sp = "after"`,
+		stringTarget{S: "  indented\n", SP: ptr("after")},
+		"",
+	},
 	"string invalid": {
 		`s = []`,
 		stringTarget{S: ""},
-		".s: invalid node type ]\nline(0) pos(5)",
+		".s: invalid node type [\nline(1) pos(5)\n1 | s = []\n  |     ^",
 	},
 	"*string valid": {
 		`sp = "a string"`,
@@ -32,10 +159,21 @@ var stringUnmarshalTests = map[string]unmarshalTest{
 	"*string invalid": {
 		`sp = []`,
 		stringTarget{},
-		".sp: invalid node type ]\nline(0) pos(6)",
+		".sp: invalid node type [\nline(1) pos(6)\n1 | sp = []\n  |      ^",
 	},
 }
 
+func TestMarshalStringEscapes(t *testing.T) {
+	target := stringTarget{S: "line one\n\tline \"two\" \\ \u00e9\x00"}
+
+	document, err := icl.MarshalString(target)
+	require.Nil(t, err)
+
+	var decoded stringTarget
+	require.Nil(t, icl.UnMarshalString(document, &decoded))
+	require.Equal(t, target, decoded)
+}
+
 func TestUnmarshalString(t *testing.T) {
 	for key, test := range stringUnmarshalTests {
 		t.Run(key, func(t *testing.T) {
@@ -0,0 +1,164 @@
+package test
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/indeedhat/icl"
+	"github.com/stretchr/testify/require"
+)
+
+type level int
+
+const (
+	levelDebug level = iota
+	levelInfo
+	levelError
+)
+
+func (l level) MarshalText() ([]byte, error) {
+	switch l {
+	case levelDebug:
+		return []byte("debug"), nil
+	case levelInfo:
+		return []byte("info"), nil
+	case levelError:
+		return []byte("error"), nil
+	}
+
+	return nil, fmt.Errorf("unknown level %d", l)
+}
+
+func (l *level) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "debug":
+		*l = levelDebug
+	case "info":
+		*l = levelInfo
+	case "error":
+		*l = levelError
+	default:
+		return fmt.Errorf("unknown level %q", text)
+	}
+
+	return nil
+}
+
+type textTarget struct {
+	IP       net.IP           `icl:"ip"`
+	Time     time.Time        `icl:"time"`
+	TimePtr  *time.Time       `icl:"time_ptr"`
+	Level    level            `icl:"level"`
+	Default  level            `icl:"default,default=error"`
+	Levels   []level          `icl:"levels"`
+	LevelMap map[string]level `icl:"level_map"`
+	IPs      []net.IP         `icl:"ips"`
+}
+
+var textUnmarshalTests = map[string]unmarshalTest{
+	"ip valid": {
+		`ip = "10.0.0.1"`,
+		textTarget{IP: net.ParseIP("10.0.0.1"), Default: levelError},
+		"",
+	},
+	"ip invalid": {
+		`ip = "not an ip"`,
+		textTarget{},
+		".ip: invalid IP address: not an ip\nline(1) pos(6)\n1 | ip = \"not an ip\"\n  |      ^",
+	},
+	"time valid": {
+		`time = "2024-01-02T03:04:05Z"`,
+		textTarget{Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Default: levelError},
+		"",
+	},
+	"time pointer valid": {
+		`time_ptr = "2024-01-02T03:04:05Z"`,
+		textTarget{TimePtr: ptr(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)), Default: levelError},
+		"",
+	},
+	"enum valid": {
+		`level = "info"`,
+		textTarget{Level: levelInfo, Default: levelError},
+		"",
+	},
+	"enum invalid": {
+		`level = "loud"`,
+		textTarget{},
+		".level: unknown level \"loud\"\nline(1) pos(9)\n1 | level = \"loud\"\n  |         ^",
+	},
+	"enum not a string": {
+		`level = 1`,
+		textTarget{Level: levelInfo, Default: levelError},
+		"",
+	},
+	"enum default": {
+		`default = "debug"`,
+		textTarget{Default: levelDebug},
+		"",
+	},
+	"enum slice": {
+		`levels = ["debug", "error"]`,
+		textTarget{Levels: []level{levelDebug, levelError}, Default: levelError},
+		"",
+	},
+	"enum map": {
+		`level_map = {api: "info", db: "error"}`,
+		textTarget{LevelMap: map[string]level{"api": levelInfo, "db": levelError}, Default: levelError},
+		"",
+	},
+	"ip slice": {
+		`ips = ["10.0.0.1", "::1"]`,
+		textTarget{IPs: []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("::1")}, Default: levelError},
+		"",
+	},
+}
+
+func TestUnmarshalText(t *testing.T) {
+	for key, test := range textUnmarshalTests {
+		t.Run(key, func(t *testing.T) {
+			tgt := textTarget{}
+			err := icl.UnMarshalString(test.document, &tgt)
+
+			if test.error != "" {
+				require.NotNil(t, err)
+				require.Equal(t, test.error, err.Error())
+			} else {
+				require.Nil(t, err)
+			}
+
+			require.Equal(t, test.output, tgt)
+		})
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	target := textTarget{
+		IP:       net.ParseIP("10.0.0.1"),
+		Time:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level:    levelInfo,
+		Default:  levelError,
+		Levels:   []level{levelDebug, levelError},
+		LevelMap: map[string]level{"api": levelInfo},
+		IPs:      []net.IP{net.ParseIP("::1")},
+	}
+
+	document, err := icl.MarshalString(target)
+	require.Nil(t, err)
+	require.Equal(t, `ip = "10.0.0.1"
+time = "2024-01-02T03:04:05Z"
+time_ptr = null
+level = "info"
+default = "error"
+levels = ["debug", "error"]
+level_map = {
+    "api": "info",
+}
+ips = ["::1"]
+`, document)
+
+	var decoded textTarget
+	require.Nil(t, icl.UnMarshalString(document, &decoded))
+	require.Equal(t, target, decoded)
+}
@@ -0,0 +1,162 @@
+package test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/indeedhat/icl"
+	"github.com/stretchr/testify/require"
+)
+
+type timeTarget struct {
+	Timeout     time.Duration            `icl:"timeout"`
+	TimeoutPtr  *time.Duration           `icl:"timeout_ptr"`
+	Default     time.Duration            `icl:"default,default=1m30s"`
+	Retries     []time.Duration          `icl:"retries"`
+	Deadlines   map[string]time.Duration `icl:"deadlines"`
+	EnvTimeout  time.Duration            `icl:"env_timeout"`
+	Created     time.Time                `icl:"created"`
+	Day         time.Time                `icl:"day,layout(2006-01-02)"`
+	Human       time.Time                `icl:"human,layout(Jan 2, 2006)"`
+	DayPtr      *time.Time               `icl:"day_ptr,layout(2006-01-02)"`
+	DefaultDays []time.Time              `icl:"default_days,layout(2006-01-02),default=[\"2024-03-04\"]"`
+}
+
+var defaultDays = []time.Time{time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)}
+
+var timeUnmarshalTests = map[string]unmarshalTest{
+	"duration string": {
+		`timeout = "1h15m"`,
+		timeTarget{Timeout: time.Hour + 15*time.Minute, Default: 90 * time.Second, DefaultDays: defaultDays},
+		"",
+	},
+	"duration nanoseconds": {
+		`timeout = 30000000000`,
+		timeTarget{Timeout: 30 * time.Second, Default: 90 * time.Second, DefaultDays: defaultDays},
+		"",
+	},
+	"duration pointer": {
+		`timeout_ptr = "250ms"`,
+		timeTarget{TimeoutPtr: ptr(250 * time.Millisecond), Default: 90 * time.Second, DefaultDays: defaultDays},
+		"",
+	},
+	"duration invalid": {
+		`timeout = "soon"`,
+		timeTarget{},
+		".timeout: time: invalid duration \"soon\"\nline(1) pos(11)\n1 | timeout = \"soon\"\n  |           ^",
+	},
+	"duration default": {
+		`default = "5s"`,
+		timeTarget{Default: 5 * time.Second, DefaultDays: defaultDays},
+		"",
+	},
+	"duration slice": {
+		`retries = ["1s", "2s", "4s"]`,
+		timeTarget{
+			Retries:     []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+			Default:     90 * time.Second,
+			DefaultDays: defaultDays,
+		},
+		"",
+	},
+	"duration map": {
+		`deadlines = {read: "5s", write: "10s"}`,
+		timeTarget{
+			Deadlines:   map[string]time.Duration{"read": 5 * time.Second, "write": 10 * time.Second},
+			Default:     90 * time.Second,
+			DefaultDays: defaultDays,
+		},
+		"",
+	},
+	"duration env": {
+		`env_timeout = env(ICL_TEST_TIMEOUT)`,
+		timeTarget{EnvTimeout: 45 * time.Second, Default: 90 * time.Second, DefaultDays: defaultDays},
+		"",
+	},
+	"time rfc3339": {
+		`created = "2024-01-02T03:04:05+01:00"`,
+		timeTarget{
+			Created:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3600)),
+			Default:     90 * time.Second,
+			DefaultDays: defaultDays,
+		},
+		"",
+	},
+	"time layout": {
+		`day = "2024-01-02"`,
+		timeTarget{Day: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Default: 90 * time.Second, DefaultDays: defaultDays},
+		"",
+	},
+	"time layout with comma": {
+		`human = "Feb 3, 2024"`,
+		timeTarget{Human: time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC), Default: 90 * time.Second, DefaultDays: defaultDays},
+		"",
+	},
+	"time layout pointer": {
+		`day_ptr = "2024-01-02"`,
+		timeTarget{DayPtr: ptr(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)), Default: 90 * time.Second, DefaultDays: defaultDays},
+		"",
+	},
+	"time layout invalid": {
+		`day = "2024-01-02T03:04:05Z"`,
+		timeTarget{},
+		".day: parsing time \"2024-01-02T03:04:05Z\": extra text: \"T03:04:05Z\"\nline(1) pos(7)\n1 | day = \"2024-01-02T03:04:05Z\"\n  |       ^",
+	},
+}
+
+func TestUnmarshalTime(t *testing.T) {
+	os.Setenv("ICL_TEST_TIMEOUT", "45s")
+	defer os.Unsetenv("ICL_TEST_TIMEOUT")
+
+	for key, test := range timeUnmarshalTests {
+		t.Run(key, func(t *testing.T) {
+			tgt := timeTarget{}
+			err := icl.UnMarshalString(test.document, &tgt)
+
+			if test.error != "" {
+				require.NotNil(t, err)
+				require.Equal(t, test.error, err.Error())
+			} else {
+				require.Nil(t, err)
+			}
+
+			require.Equal(t, test.output, tgt)
+		})
+	}
+}
+
+func TestMarshalTime(t *testing.T) {
+	target := timeTarget{
+		Timeout:     time.Hour + 30*time.Minute,
+		TimeoutPtr:  ptr(2 * time.Minute),
+		Default:     90 * time.Second,
+		Retries:     []time.Duration{time.Second, 3 * time.Hour},
+		Deadlines:   map[string]time.Duration{"read": 1500 * time.Millisecond},
+		Created:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Day:         time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Human:       time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC),
+		DefaultDays: defaultDays,
+	}
+
+	document, err := icl.MarshalString(target)
+	require.Nil(t, err)
+	require.Equal(t, `timeout = "1h30m"
+timeout_ptr = "2m"
+default = "1m30s"
+retries = ["1s", "3h"]
+deadlines = {
+    "read": "1.5s",
+}
+env_timeout = "0s"
+created = "2024-01-02T03:04:05Z"
+day = "2024-01-02"
+human = "Feb 3, 2024"
+day_ptr = null
+default_days = ["2024-03-04"]
+`, document)
+
+	var decoded timeTarget
+	require.Nil(t, icl.UnMarshalString(document, &decoded))
+	require.Equal(t, target, decoded)
+}
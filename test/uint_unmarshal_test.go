@@ -23,10 +23,26 @@ var uintUnmarshalTests = map[string]unmarshalTest{
 		uintTarget{I: uint(math.MaxUint)},
 		"",
 	},
+	"uint file mode": {
+		`i32 = 0o644`,
+		uintTarget{I32: 0o644},
+		"",
+	},
+	"uint bitmask": {
+		`i8 = 0xFF
+		i16 = 0b1111_0000`,
+		uintTarget{I8: 0xFF, I16: 0xF0},
+		"",
+	},
+	"uint exponent": {
+		`i = 2e3`,
+		uintTarget{I: 2000},
+		"",
+	},
 	"uint bad type": {
 		`i = "bad"`,
 		uintTarget{},
-		".i: invalid uint type string\nline(0) pos(6)",
+		".i: invalid uint type string\nline(1) pos(5)\n1 | i = \"bad\"\n  |     ^",
 	},
 	"uint8 valid": {
 		`i8 = 255`,
@@ -36,12 +52,12 @@ var uintUnmarshalTests = map[string]unmarshalTest{
 	"uint8 bad type": {
 		`i8 = "bad"`,
 		uintTarget{},
-		".i8: invalid uint8 type string\nline(0) pos(7)",
+		".i8: invalid uint8 type string\nline(1) pos(6)\n1 | i8 = \"bad\"\n  |      ^",
 	},
 	"uint8 too large": {
 		`i8 = 256`,
 		uintTarget{},
-		".i8: strconv.ParseUint: parsing \"256\": value out of range\nline(0) pos(5)",
+		".i8: strconv.ParseUint: parsing \"256\": value out of range\nline(1) pos(6)\n1 | i8 = 256\n  |      ^",
 	},
 	"uint16 valid": {
 		`i16 = 65535`,
@@ -51,12 +67,12 @@ var uintUnmarshalTests = map[string]unmarshalTest{
 	"uint16 bad type": {
 		`i16 = "bad"`,
 		uintTarget{},
-		".i16: invalid uint16 type string\nline(0) pos(8)",
+		".i16: invalid uint16 type string\nline(1) pos(7)\n1 | i16 = \"bad\"\n  |       ^",
 	},
 	"uint16 too large": {
 		`i16 = 65536`,
 		uintTarget{},
-		".i16: strconv.ParseUint: parsing \"65536\": value out of range\nline(0) pos(6)",
+		".i16: strconv.ParseUint: parsing \"65536\": value out of range\nline(1) pos(7)\n1 | i16 = 65536\n  |       ^",
 	},
 	"uint32 valid": {
 		`i32 = 4294967295`,
@@ -66,12 +82,12 @@ var uintUnmarshalTests = map[string]unmarshalTest{
 	"uint32 bad type": {
 		`i32 = "bad"`,
 		uintTarget{},
-		".i32: invalid uint32 type string\nline(0) pos(8)",
+		".i32: invalid uint32 type string\nline(1) pos(7)\n1 | i32 = \"bad\"\n  |       ^",
 	},
 	"uint32 too large": {
 		`i32 = 4294967296`,
 		uintTarget{},
-		".i32: strconv.ParseUint: parsing \"4294967296\": value out of range\nline(0) pos(6)",
+		".i32: strconv.ParseUint: parsing \"4294967296\": value out of range\nline(1) pos(7)\n1 | i32 = 4294967296\n  |       ^",
 	},
 	"uint64 valid": {
 		`i64 = 18446744073709551615`,
@@ -81,7 +97,7 @@ var uintUnmarshalTests = map[string]unmarshalTest{
 	"uint64 bad type": {
 		`i64 = "bad"`,
 		uintTarget{},
-		".i64: invalid uint64 type string\nline(0) pos(8)",
+		".i64: invalid uint64 type string\nline(1) pos(7)\n1 | i64 = \"bad\"\n  |       ^",
 	},
 }
 
@@ -120,7 +136,7 @@ var uintPtrUnmarshalTests = map[string]unmarshalTest{
 	"uint bad type": {
 		`i = "bad"`,
 		uintPtrTarget{},
-		".i: invalid uint type string\nline(0) pos(6)",
+		".i: invalid uint type string\nline(1) pos(5)\n1 | i = \"bad\"\n  |     ^",
 	},
 	"uint8 valid": {
 		`i8 = 255`,
@@ -130,12 +146,12 @@ var uintPtrUnmarshalTests = map[string]unmarshalTest{
 	"uint8 bad type": {
 		`i8 = "bad"`,
 		uintPtrTarget{},
-		".i8: invalid uint8 type string\nline(0) pos(7)",
+		".i8: invalid uint8 type string\nline(1) pos(6)\n1 | i8 = \"bad\"\n  |      ^",
 	},
 	"uint8 too large": {
 		`i8 = 256`,
 		uintPtrTarget{},
-		".i8: strconv.ParseUint: parsing \"256\": value out of range\nline(0) pos(5)",
+		".i8: strconv.ParseUint: parsing \"256\": value out of range\nline(1) pos(6)\n1 | i8 = 256\n  |      ^",
 	},
 	"uint16 valid": {
 		`i16 = 65535`,
@@ -145,12 +161,12 @@ var uintPtrUnmarshalTests = map[string]unmarshalTest{
 	"uint16 bad type": {
 		`i16 = "bad"`,
 		uintPtrTarget{},
-		".i16: invalid uint16 type string\nline(0) pos(8)",
+		".i16: invalid uint16 type string\nline(1) pos(7)\n1 | i16 = \"bad\"\n  |       ^",
 	},
 	"uint16 too large": {
 		`i16 = 65536`,
 		uintPtrTarget{},
-		".i16: strconv.ParseUint: parsing \"65536\": value out of range\nline(0) pos(6)",
+		".i16: strconv.ParseUint: parsing \"65536\": value out of range\nline(1) pos(7)\n1 | i16 = 65536\n  |       ^",
 	},
 	"uint32 valid": {
 		`i32 = 4294967295`,
@@ -160,12 +176,12 @@ var uintPtrUnmarshalTests = map[string]unmarshalTest{
 	"uint32 bad type": {
 		`i32 = "bad"`,
 		uintPtrTarget{},
-		".i32: invalid uint32 type string\nline(0) pos(8)",
+		".i32: invalid uint32 type string\nline(1) pos(7)\n1 | i32 = \"bad\"\n  |       ^",
 	},
 	"uint32 too large": {
 		`i32 = 4294967296`,
 		uintPtrTarget{},
-		".i32: strconv.ParseUint: parsing \"4294967296\": value out of range\nline(0) pos(6)",
+		".i32: strconv.ParseUint: parsing \"4294967296\": value out of range\nline(1) pos(7)\n1 | i32 = 4294967296\n  |       ^",
 	},
 	"uint64 valid": {
 		`i64 = 18446744073709551615`,
@@ -175,7 +191,7 @@ var uintPtrUnmarshalTests = map[string]unmarshalTest{
 	"uint64 bad type": {
 		`i64 = "bad"`,
 		uintPtrTarget{},
-		".i64: invalid uint64 type string\nline(0) pos(8)",
+		".i64: invalid uint64 type string\nline(1) pos(7)\n1 | i64 = \"bad\"\n  |       ^",
 	},
 }
 
@@ -0,0 +1,53 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/indeedhat/icl"
+	"github.com/stretchr/testify/require"
+)
+
+type unknownTarget struct {
+	Name   string       `icl:"name"`
+	Server unknownBlock `icl:"server"`
+}
+
+type unknownBlock struct {
+	Host string `icl:"host"`
+}
+
+const unknownDocument = `name = "name"
+nmae = "typo"
+server {
+	host = "localhost"
+	prot = 80
+}
+sever {
+	host = "typo"
+}`
+
+func TestUnmarshalUnknownFieldsAllowed(t *testing.T) {
+	tgt := unknownTarget{}
+	err := icl.UnMarshalString(unknownDocument, &tgt)
+
+	require.Nil(t, err)
+	require.Equal(t, unknownTarget{Name: "name", Server: unknownBlock{Host: "localhost"}}, tgt)
+}
+
+func TestUnmarshalDisallowUnknownFields(t *testing.T) {
+	tgt := unknownTarget{}
+	err := icl.UnMarshalStringWithOptions(unknownDocument, &tgt, icl.DecodeOptions{DisallowUnknownFields: true})
+
+	var unknownErr *icl.UnknownFieldsError
+	require.True(t, errors.As(err, &unknownErr))
+	require.Equal(t, []icl.UnknownField{
+		{Path: ".nmae", Line: 2, Pos: 1},
+		{Path: ".server.prot", Line: 5, Pos: 2},
+		{Path: ".sever", Line: 7, Pos: 1},
+	}, unknownErr.Fields)
+	require.Equal(t, `icl: unknown fields
+.nmae -- [line(2) pos(1)]
+.server.prot -- [line(5) pos(2)]
+.sever -- [line(7) pos(1)]`, err.Error())
+}
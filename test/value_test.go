@@ -0,0 +1,114 @@
+package test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/indeedhat/icl"
+	"github.com/stretchr/testify/require"
+)
+
+const valueDocument = `version = 1
+name = "dashboard"
+ratio = 0.75
+tags = ["a", "b"]
+limits = {max: 10, min: null}
+
+server "api" "internal" {
+    port = 8080
+
+    route "/health" {}
+    route "/metrics" {}
+}
+
+server "web" {
+    port = 80
+}
+`
+
+func TestUnmarshalMap(t *testing.T) {
+	m, err := icl.UnmarshalMap([]byte(valueDocument))
+
+	require.Nil(t, err)
+	require.Equal(t, map[string]any{
+		"version": int64(1),
+		"name":    "dashboard",
+		"ratio":   0.75,
+		"tags":    []any{"a", "b"},
+		"limits":  map[string]any{"max": int64(10), "min": nil},
+		"server": []any{
+			map[string]any{
+				icl.ParamsKey: []any{"api", "internal"},
+				"port":        int64(8080),
+				"route": []any{
+					map[string]any{icl.ParamsKey: []any{"/health"}},
+					map[string]any{icl.ParamsKey: []any{"/metrics"}},
+				},
+			},
+			map[string]any{
+				icl.ParamsKey: []any{"web"},
+				"port":        int64(80),
+			},
+		},
+	}, m)
+}
+
+func TestUnmarshalValue(t *testing.T) {
+	v, err := icl.UnmarshalValue([]byte(valueDocument))
+	require.Nil(t, err)
+
+	require.Equal(t, icl.MapValue, v.Kind())
+	require.Equal(t, []string{"version", "name", "ratio", "tags", "limits", "server"}, v.Keys())
+
+	name, ok := v.Get("name").AsString()
+	require.True(t, ok)
+	require.Equal(t, "dashboard", name)
+
+	_, ok = v.Get("name").AsInt()
+	require.False(t, ok)
+
+	ratio, ok := v.Get("ratio").AsFloat()
+	require.True(t, ok)
+	require.Equal(t, 0.75, ratio)
+
+	require.Equal(t, icl.NullValue, v.Get("limits").Get("min").Kind())
+	require.Equal(t, icl.NullValue, v.Get("missing").Kind())
+
+	servers := v.Get("server").List()
+	require.Len(t, servers, 2)
+	require.Equal(t, icl.BlockValue, servers[0].Kind())
+	require.Equal(t, []string{"api", "internal"}, servers[0].Params())
+	require.Equal(t, []string{"port", "route"}, servers[0].Keys())
+
+	port, ok := servers[1].Get("port").AsInt()
+	require.True(t, ok)
+	require.Equal(t, int64(80), port)
+
+	line, pos := servers[1].Position()
+	require.Equal(t, 14, line)
+	require.Equal(t, 1, pos)
+}
+
+func TestUnmarshalValueError(t *testing.T) {
+	_, err := icl.UnmarshalMap([]byte(`bad = [ident]`))
+
+	require.NotNil(t, err)
+	require.Equal(t, ".bad: invalid node type IDENT\nline(1) pos(8)\n1 | bad = [ident]\n  |        ^", err.Error())
+}
+
+func TestUnmarshalMapNumbers(t *testing.T) {
+	m, err := icl.UnmarshalMap([]byte(`mode = 0o755
+mask = 0xFF
+count = 1_000
+big = 1e6
+limits = {inf: -inf}`))
+
+	require.Nil(t, err)
+	require.Equal(t, map[string]any{
+		"mode":   int64(0o755),
+		"mask":   int64(0xFF),
+		"count":  int64(1000),
+		"big":    1e6,
+		"limits": map[string]any{"inf": math.Inf(-1)},
+	}, m)
+}
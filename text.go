@@ -0,0 +1,65 @@
+package icl
+
+import (
+	"encoding"
+	"reflect"
+)
+
+var (
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// isTextType checks if the type or a pointer to it implements either encoding.TextMarshaler or
+// encoding.TextUnmarshaler
+func isTextType(rt reflect.Type) bool {
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+
+	pt := reflect.PointerTo(rt)
+
+	return rt.Implements(textMarshalerType) ||
+		rt.Implements(textUnmarshalerType) ||
+		pt.Implements(textMarshalerType) ||
+		pt.Implements(textUnmarshalerType)
+}
+
+// textUnmarshaler returns the encoding.TextUnmarshaler implemented by the value
+// nil pointers will be allocated so they can be unmarshaled into
+func textUnmarshaler(rv reflect.Value) (encoding.TextUnmarshaler, bool) {
+	rt := rv.Type()
+
+	if rt.Kind() == reflect.Pointer && rt.Implements(textUnmarshalerType) {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rt.Elem()))
+		}
+
+		return rv.Interface().(encoding.TextUnmarshaler), true
+	}
+
+	if rv.CanAddr() && reflect.PointerTo(rt).Implements(textUnmarshalerType) {
+		return rv.Addr().Interface().(encoding.TextUnmarshaler), true
+	}
+
+	return nil, false
+}
+
+// textMarshaler returns the encoding.TextMarshaler implemented by the value
+func textMarshaler(rv reflect.Value) (encoding.TextMarshaler, bool) {
+	rt := rv.Type()
+
+	if rt.Implements(textMarshalerType) {
+		if rt.Kind() == reflect.Pointer && rv.IsNil() {
+			return nil, false
+		}
+
+		return rv.Interface().(encoding.TextMarshaler), true
+	}
+
+	if rv.CanAddr() && reflect.PointerTo(rt).Implements(textMarshalerType) {
+		return rv.Addr().Interface().(encoding.TextMarshaler), true
+	}
+
+	return nil, false
+}
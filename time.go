@@ -0,0 +1,50 @@
+package icl
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeFor[time.Duration]()
+	timeType     = reflect.TypeFor[time.Time]()
+)
+
+// isType checks if the type or the type it points to matches the expected type
+func isType(rt reflect.Type, expected reflect.Type) bool {
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+
+	return rt == expected
+}
+
+// formatDuration formats the duration in its most readable form, trailing zero units are removed so 1h30m0s
+// becomes 1h30m
+func formatDuration(d time.Duration) string {
+	s := d.String()
+
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+
+	return s
+}
+
+// stringValue returns the text of string and env() nodes
+func stringValue(node Node) (string, bool) {
+	switch n := node.(type) {
+	case *StringNode:
+		return n.Value, true
+	case *EnvarNode:
+		return os.Getenv(n.Identifier.Value), true
+	}
+
+	return "", false
+}
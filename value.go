@@ -0,0 +1,350 @@
+package icl
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strconv"
+)
+
+// ValueKind identifies the type of data held by a Value
+type ValueKind int
+
+const (
+	NullValue ValueKind = iota
+	StringValue
+	BoolValue
+	IntValue
+	FloatValue
+	ListValue
+	MapValue
+	BlockValue
+)
+
+// String implements fmt.Stringer
+func (k ValueKind) String() string {
+	switch k {
+	case NullValue:
+		return "null"
+	case StringValue:
+		return "string"
+	case BoolValue:
+		return "bool"
+	case IntValue:
+		return "int"
+	case FloatValue:
+		return "float"
+	case ListValue:
+		return "list"
+	case MapValue:
+		return "map"
+	case BlockValue:
+		return "block"
+	}
+
+	return "unknown"
+}
+
+// Value is a generic representation of the data in an icl document
+//
+// The root of a document is a MapValue, blocks are BlockValues with their params preserved and blocks that are
+// repeated within the same scope are collected into a ListValue in the order they are found
+type Value struct {
+	kind ValueKind
+	line int
+	pos  int
+
+	str    string
+	b      bool
+	i      int64
+	f      float64
+	list   []*Value
+	keys   []string
+	fields map[string]*Value
+	params []string
+}
+
+// Kind returns the type of data held by the value
+func (v *Value) Kind() ValueKind {
+	if v == nil {
+		return NullValue
+	}
+
+	return v.kind
+}
+
+// Position returns the line and column the value was found at in the document
+func (v *Value) Position() (line, pos int) {
+	if v == nil {
+		return 0, 0
+	}
+
+	return v.line, v.pos
+}
+
+// AsString returns the string held by the value
+func (v *Value) AsString() (string, bool) {
+	if v.Kind() != StringValue {
+		return "", false
+	}
+
+	return v.str, true
+}
+
+// AsBool returns the bool held by the value
+func (v *Value) AsBool() (bool, bool) {
+	if v.Kind() != BoolValue {
+		return false, false
+	}
+
+	return v.b, true
+}
+
+// AsInt returns the integer held by the value
+func (v *Value) AsInt() (int64, bool) {
+	if v.Kind() != IntValue {
+		return 0, false
+	}
+
+	return v.i, true
+}
+
+// AsFloat returns the number held by the value, integer values will be converted to a float64
+func (v *Value) AsFloat() (float64, bool) {
+	switch v.Kind() {
+	case FloatValue:
+		return v.f, true
+	case IntValue:
+		return float64(v.i), true
+	}
+
+	return 0, false
+}
+
+// List returns the elements of a ListValue
+func (v *Value) List() []*Value {
+	if v.Kind() != ListValue {
+		return nil
+	}
+
+	return v.list
+}
+
+// Keys returns the keys of a MapValue or BlockValue in the order they were found in the document
+func (v *Value) Keys() []string {
+	if v.Kind() != MapValue && v.Kind() != BlockValue {
+		return nil
+	}
+
+	return v.keys
+}
+
+// Get returns the value stored against the key in a MapValue or BlockValue
+// nil will be returned if the key does not exist
+func (v *Value) Get(key string) *Value {
+	if v.Kind() != MapValue && v.Kind() != BlockValue {
+		return nil
+	}
+
+	return v.fields[key]
+}
+
+// Params returns the params of a BlockValue
+func (v *Value) Params() []string {
+	if v.Kind() != BlockValue {
+		return nil
+	}
+
+	return v.params
+}
+
+// Interface converts the value into its natural go type
+//
+// lists become []any, maps and blocks become map[string]any with block params stored as a []any under the
+// ParamsKey key
+func (v *Value) Interface() any {
+	switch v.Kind() {
+	case StringValue:
+		return v.str
+	case BoolValue:
+		return v.b
+	case IntValue:
+		return v.i
+	case FloatValue:
+		return v.f
+	case ListValue:
+		values := make([]any, 0, len(v.list))
+		for _, elem := range v.list {
+			values = append(values, elem.Interface())
+		}
+
+		return values
+	case MapValue, BlockValue:
+		values := make(map[string]any, len(v.fields))
+		for key, field := range v.fields {
+			values[key] = field.Interface()
+		}
+
+		if len(v.params) > 0 {
+			params := make([]any, 0, len(v.params))
+			for _, param := range v.params {
+				params = append(params, param)
+			}
+
+			values[ParamsKey] = params
+		}
+
+		return values
+	}
+
+	return nil
+}
+
+// set stores the field against the key keeping track of the order keys are added
+func (v *Value) set(key string, field *Value) {
+	if _, ok := v.fields[key]; !ok {
+		v.keys = append(v.keys, key)
+	}
+
+	v.fields[key] = field
+}
+
+// nodeValue converts an expression node into a Value
+func nodeValue(node Node, path string) (*Value, error) {
+	value := &Value{line: node.Tkn().Line, pos: node.Tkn().Pos}
+
+	switch n := node.(type) {
+	case *StringNode:
+		value.kind = StringValue
+		value.str = n.Value
+	case *BooleanNode:
+		value.kind = BoolValue
+		value.b = n.Value
+	case *NullNode:
+		value.kind = NullValue
+	case *EnvarNode:
+		value.kind = StringValue
+		value.str = os.Getenv(n.Identifier.Value)
+	case *NumberNode:
+		if i, err := strconv.ParseInt(intLiteral(n.Value), 0, 64); err == nil {
+			value.kind = IntValue
+			value.i = i
+			break
+		}
+
+		f, err := parseFloat(n.Value, 64)
+		if err != nil {
+			return nil, valueError(node, path, err)
+		}
+
+		value.kind = FloatValue
+		value.f = f
+	case *SliceNode:
+		value.kind = ListValue
+		value.list = make([]*Value, 0, len(n.Elements))
+
+		for _, elem := range n.Elements {
+			v, err := nodeValue(elem, path)
+			if err != nil {
+				return nil, err
+			}
+
+			value.list = append(value.list, v)
+		}
+	case *MapNode:
+		value.kind = MapValue
+		value.fields = make(map[string]*Value, len(n.Elements))
+
+		for _, key := range n.Keys() {
+			v, err := nodeValue(n.Elements[key], path)
+			if err != nil {
+				return nil, err
+			}
+
+			switch k := key.(type) {
+			case *StringNode:
+				value.set(k.Value, v)
+			case *Identifier:
+				value.set(k.Value, v)
+			default:
+				return nil, valueError(key, path, errors.New("Map keys must be a string"))
+			}
+		}
+	default:
+		return nil, valueError(node, path, errors.New("invalid node type "+string(node.Tkn().Type)))
+	}
+
+	return value, nil
+}
+
+// blockValue converts a block node into a BlockValue
+func blockValue(node *BlockNode, path string) (*Value, error) {
+	value, err := nodesValue(node.Body.Nodes, path)
+	if err != nil {
+		return nil, err
+	}
+
+	value.kind = BlockValue
+	value.line = node.Token.Line
+	value.pos = node.Token.Pos
+
+	for _, param := range node.Parameters {
+		value.params = append(value.params, param.Literal)
+	}
+
+	return value, nil
+}
+
+// nodesValue converts a list of statements into a MapValue
+// blocks that appear more than once are collected into a ListValue in the order they are found
+func nodesValue(nodes []Node, path string) (*Value, error) {
+	value := &Value{kind: MapValue, fields: make(map[string]*Value)}
+	blocks := make(map[string]int)
+
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case *AssignNode:
+			v, err := nodeValue(n.Value, path+"."+n.Name.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			value.set(n.Name.Value, v)
+			delete(blocks, n.Name.Value)
+
+		case *BlockNode:
+			v, err := blockValue(n, path+"."+n.TokenLiteral())
+			if err != nil {
+				return nil, err
+			}
+
+			key := n.TokenLiteral()
+			switch blocks[key] {
+			case 0:
+				value.set(key, v)
+			case 1:
+				first := value.fields[key]
+				value.set(key, &Value{kind: ListValue, line: first.line, pos: first.pos, list: []*Value{first, v}})
+			default:
+				list := value.fields[key]
+				list.list = append(list.list, v)
+			}
+
+			blocks[key]++
+		}
+	}
+
+	return value, nil
+}
+
+// valueError wraps the error in a *DecodeError pointing at the node that caused it
+func valueError(node Node, path string, err error) *DecodeError {
+	return &DecodeError{
+		Path:     path,
+		Line:     node.Tkn().Line,
+		Pos:      node.Tkn().Pos,
+		Expected: reflect.Interface,
+		Found:    node.Tkn().Type,
+		Err:      err,
+	}
+}